@@ -2,30 +2,65 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/time_capsule/memory-service/config"
 	"github.com/time_capsule/memory-service/genproto/memory"
 	"github.com/time_capsule/memory-service/kafka/consumer"
 	"github.com/time_capsule/memory-service/service"
+	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/storage/mongo"
 	"github.com/time_capsule/memory-service/storage/postgres"
+	"github.com/time_capsule/memory-service/storage/sqlite"
 	"google.golang.org/grpc"
 )
 
 func main() {
 	cfg := config.Load()
 
-	// Initialize PostgreSQL storage
-	storage, err := postgres.NewPostgresStorage(cfg)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "dlq" {
+		if err := runDLQ(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("dlq: %v", err)
+		}
+		return
+	}
+
+	// Initialize storage
+	storage, err := newStorage(cfg)
 	if err != nil {
 		log.Fatalf("failed to initialize storage: %v", err)
 	}
 
 	// Initialize Kafka consumers
-	memoryConsumer := consumer.NewMemoryConsumer(cfg.KafkaBrokers, "memory_topic", storage)
-	mediaConsumer := consumer.NewMediaConsumer(cfg.KafkaBrokers, "media_topic", storage)
-	commentConsumer := consumer.NewCommentConsumer(cfg.KafkaBrokers, "comment_topic", storage)
+	opts := consumer.Options{
+		Concurrency: cfg.KafkaConcurrency,
+		BatchSize:   cfg.KafkaBatchSize,
+		Deduplicate: cfg.KafkaDeduplicate,
+		Retry: consumer.RetryPolicy{
+			MaxAttempts:    cfg.KafkaRetryAttempts,
+			InitialBackoff: cfg.KafkaRetryInitialBackoff,
+			MaxBackoff:     cfg.KafkaRetryMaxBackoff,
+		},
+	}
+	if cfg.KafkaDeadLetterTopic != "" {
+		opts.DeadLetter = consumer.NewDeadLetterQueue(cfg.KafkaBrokers, cfg.KafkaDeadLetterTopic)
+		defer opts.DeadLetter.Close()
+	}
+	memoryConsumer := consumer.NewMemoryConsumer(cfg.KafkaBrokers, "memory_topic", storage, opts)
+	mediaConsumer := consumer.NewMediaConsumer(cfg.KafkaBrokers, "media_topic", storage, opts)
+	commentConsumer := consumer.NewCommentConsumer(cfg.KafkaBrokers, "comment_topic", storage, opts)
 
 	// Start consumers in separate goroutines
 	go func() {
@@ -46,13 +81,26 @@ func main() {
 		}
 	}()
 
+	// Permanently delete items that stayed in the trash past the retention period
+	if cfg.TrashRetentionDays > 0 && cfg.TrashPurgeInterval > 0 {
+		retention := time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+		purger := service.NewTrashPurger(storage, retention, cfg.TrashPurgeInterval)
+		go purger.Run(context.Background())
+	}
+
+	// Forget processed Kafka commands once redeliveries are no longer expected
+	if cfg.KafkaDeduplicate && cfg.ProcessedCommandRetention > 0 && cfg.ProcessedCommandPurgeInterval > 0 {
+		purger := service.NewCommandPurger(storage, cfg.ProcessedCommandRetention, cfg.ProcessedCommandPurgeInterval)
+		go purger.Run(context.Background())
+	}
+
 	// Initialize gRPC server
 	lis, err := net.Listen("tcp", cfg.HTTPPort)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(grpc.UnaryInterceptor(service.ErrorInterceptor))
 	memory.RegisterMemoryServiceServer(s, service.NewMemoryService(storage))
 	memory.RegisterMediaServiceServer(s, service.NewMediaService(storage))
 	memory.RegisterCommentServiceServer(s, service.NewCommentService(storage))
@@ -61,3 +109,121 @@ func main() {
 		log.Fatalf("failed to serve: %v", err)
 	}
 }
+
+// newStorage initializes the storage backend selected by STORAGE_DRIVER.
+func newStorage(cfg config.Config) (storage.StorageI, error) {
+	switch cfg.StorageDriver {
+	case "", "postgres":
+		return postgres.NewPostgresStorage(cfg)
+	case "mongo":
+		return mongo.NewMongoStorage(cfg)
+	case "sqlite":
+		return sqlite.NewSQLiteStorage(cfg)
+	}
+	return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+}
+
+// runMigrate implements the `migrate up|down [steps]|status` subcommand.
+func runMigrate(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: %s migrate up|down [steps]|status", os.Args[0])
+	}
+	if cfg.StorageDriver != "" && cfg.StorageDriver != "postgres" {
+		return fmt.Errorf("migrations only apply to the postgres storage driver")
+	}
+
+	ctx := context.Background()
+	db, err := postgres.NewPool(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := postgres.MigrateUp(ctx, db)
+		if err != nil {
+			return err
+		}
+		log.Printf("applied %d migration(s)", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps: %q", args[1])
+			}
+		}
+		rolledBack, err := postgres.MigrateDown(ctx, db, steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("rolled back %d migration(s)", rolledBack)
+	case "status":
+		status, err := postgres.GetMigrationStatus(ctx, db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d (dirty: %t)\n", status.Version, status.Dirty)
+		for _, m := range status.Applied {
+			fmt.Printf("  [applied] %06d_%s\n", m.Version, m.Name)
+		}
+		for _, m := range status.Pending {
+			fmt.Printf("  [pending] %06d_%s\n", m.Version, m.Name)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	return nil
+}
+
+// runDLQ implements the `dlq list|redrive [limit]` subcommand.
+func runDLQ(cfg config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: %s dlq list|redrive [limit]", os.Args[0])
+	}
+	if cfg.KafkaDeadLetterTopic == "" {
+		return fmt.Errorf("KAFKA_DEAD_LETTER_TOPIC is not set")
+	}
+	limit := 0
+	if len(args) > 1 {
+		var err error
+		if limit, err = strconv.Atoi(args[1]); err != nil || limit < 1 {
+			return fmt.Errorf("invalid limit: %q", args[1])
+		}
+	}
+
+	ctx := context.Background()
+	dlq := consumer.NewDeadLetterQueue(cfg.KafkaBrokers, cfg.KafkaDeadLetterTopic)
+	defer dlq.Close()
+
+	switch args[0] {
+	case "list":
+		msgs, err := dlq.List(ctx, limit)
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			fmt.Printf("%d@%d key=%s attempts=%s from=%s/%s@%s failed_at=%s\n  error: %s\n  value: %s\n",
+				msg.Partition, msg.Offset, msg.Key,
+				consumer.Header(msg, consumer.HeaderAttempts),
+				consumer.Header(msg, consumer.HeaderTopic),
+				consumer.Header(msg, consumer.HeaderPartition),
+				consumer.Header(msg, consumer.HeaderOffset),
+				consumer.Header(msg, consumer.HeaderFailedAt),
+				consumer.Header(msg, consumer.HeaderError),
+				msg.Value)
+		}
+		log.Printf("listed %d message(s)", len(msgs))
+	case "redrive":
+		redriven, err := dlq.Redrive(ctx, limit)
+		log.Printf("re-drove %d message(s)", redriven)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown dlq command %q", args[0])
+	}
+
+	return nil
+}
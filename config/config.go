@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cast"
@@ -12,14 +13,72 @@ import (
 type Config struct {
 	HTTPPort string
 
+	// StorageDriver selects the storage backend: "postgres", "mongo" or
+	// "sqlite".
+	StorageDriver string
+
 	// PostgreSQL Configuration
 	PostgresHost     string
 	PostgresPort     int
 	PostgresUser     string
 	PostgresPassword string
 	PostgresDB       string
-	KafkaBrokers     []string
-	LOG_PATH         string
+
+	// PostgreSQL connection pool configuration
+	PostgresMaxConns          int32
+	PostgresMinConns          int32
+	PostgresMaxConnLifetime   time.Duration
+	PostgresMaxConnIdleTime   time.Duration
+	PostgresHealthCheckPeriod time.Duration
+	PostgresConnectTimeout    time.Duration
+	PostgresConnectAttempts   int
+
+	// AutoMigrate applies pending schema migrations on startup.
+	AutoMigrate bool
+
+	// MongoDB Configuration
+	MongoURI            string
+	MongoDB             string
+	MongoConnectTimeout time.Duration
+
+	// SQLitePath is the database file of the sqlite storage driver.
+	SQLitePath string
+
+	KafkaBrokers []string
+	LOG_PATH     string
+
+	// KafkaDeadLetterTopic receives the messages the consumers fail to
+	// process; it defaults to "dead_letter_topic". Setting it to an empty
+	// value disables dead-lettering, so failures are only logged.
+	KafkaDeadLetterTopic string
+
+	// Kafka retry configuration: messages failing with a retryable error are
+	// attempted up to KafkaRetryAttempts times, waiting an exponential
+	// backoff between KafkaRetryInitialBackoff and KafkaRetryMaxBackoff.
+	KafkaRetryAttempts       int
+	KafkaRetryInitialBackoff time.Duration
+	KafkaRetryMaxBackoff     time.Duration
+
+	// KafkaConcurrency is how many messages each consumer processes at once.
+	// Messages with the same key are still processed in order.
+	KafkaConcurrency int
+
+	// KafkaBatchSize is how many queued messages a consumer worker takes at
+	// once; consecutive creates among them are written with one bulk insert.
+	KafkaBatchSize int
+
+	// KafkaDeduplicate records the commands the consumers apply, so that
+	// redelivered commands are skipped. Processed commands are remembered
+	// for ProcessedCommandRetention (0 keeps them forever), checked every
+	// ProcessedCommandPurgeInterval.
+	KafkaDeduplicate              bool
+	ProcessedCommandRetention     time.Duration
+	ProcessedCommandPurgeInterval time.Duration
+
+	// Trash configuration: trashed items are purged after TrashRetentionDays
+	// (0 disables purging), checked every TrashPurgeInterval.
+	TrashRetentionDays int
+	TrashPurgeInterval time.Duration
 }
 
 // Load loads the configuration from environment variables.
@@ -32,6 +91,8 @@ func Load() Config {
 
 	config.HTTPPort = cast.ToString(coalesce("HTTP_PORT", ":9090"))
 
+	config.StorageDriver = cast.ToString(coalesce("STORAGE_DRIVER", "postgres"))
+
 	// PostgreSQL Configuration
 	config.PostgresHost = cast.ToString(coalesce("POSTGRES_HOST", "postgres_dock"))
 	config.PostgresPort = cast.ToInt(coalesce("POSTGRES_PORT", 5432))
@@ -39,10 +100,41 @@ func Load() Config {
 	config.PostgresPassword = cast.ToString(coalesce("POSTGRES_PASSWORD", "example"))
 	config.PostgresDB = cast.ToString(coalesce("POSTGRES_DB", "memory"))
 
+	// PostgreSQL connection pool configuration
+	config.PostgresMaxConns = cast.ToInt32(coalesce("POSTGRES_MAX_CONNS", 20))
+	config.PostgresMinConns = cast.ToInt32(coalesce("POSTGRES_MIN_CONNS", 2))
+	config.PostgresMaxConnLifetime = cast.ToDuration(coalesce("POSTGRES_MAX_CONN_LIFETIME", "1h"))
+	config.PostgresMaxConnIdleTime = cast.ToDuration(coalesce("POSTGRES_MAX_CONN_IDLE_TIME", "30m"))
+	config.PostgresHealthCheckPeriod = cast.ToDuration(coalesce("POSTGRES_HEALTH_CHECK_PERIOD", "1m"))
+	config.PostgresConnectTimeout = cast.ToDuration(coalesce("POSTGRES_CONNECT_TIMEOUT", "5s"))
+	config.PostgresConnectAttempts = cast.ToInt(coalesce("POSTGRES_CONNECT_ATTEMPTS", 5))
+
+	config.AutoMigrate = cast.ToBool(coalesce("AUTO_MIGRATE", false))
+
+	// MongoDB Configuration
+	config.MongoURI = cast.ToString(coalesce("MONGO_URI", "mongodb://mongo_dock:27017"))
+	config.MongoDB = cast.ToString(coalesce("MONGO_DB", "memory"))
+	config.MongoConnectTimeout = cast.ToDuration(coalesce("MONGO_CONNECT_TIMEOUT", "10s"))
+
+	// SQLite Configuration
+	config.SQLitePath = cast.ToString(coalesce("SQLITE_PATH", "memory.db"))
+
 	config.KafkaBrokers = cast.ToStringSlice(coalesce("KAFKA_BROKERS", []string{"kafka:9092"}))
+	config.KafkaDeadLetterTopic = cast.ToString(coalesce("KAFKA_DEAD_LETTER_TOPIC", "dead_letter_topic"))
+	config.KafkaRetryAttempts = cast.ToInt(coalesce("KAFKA_RETRY_ATTEMPTS", 5))
+	config.KafkaRetryInitialBackoff = cast.ToDuration(coalesce("KAFKA_RETRY_INITIAL_BACKOFF", "200ms"))
+	config.KafkaRetryMaxBackoff = cast.ToDuration(coalesce("KAFKA_RETRY_MAX_BACKOFF", "10s"))
+	config.KafkaConcurrency = cast.ToInt(coalesce("KAFKA_CONCURRENCY", 8))
+	config.KafkaBatchSize = cast.ToInt(coalesce("KAFKA_BATCH_SIZE", 100))
+	config.KafkaDeduplicate = cast.ToBool(coalesce("KAFKA_DEDUPLICATE", true))
+	config.ProcessedCommandRetention = cast.ToDuration(coalesce("PROCESSED_COMMAND_RETENTION", "168h"))
+	config.ProcessedCommandPurgeInterval = cast.ToDuration(coalesce("PROCESSED_COMMAND_PURGE_INTERVAL", "1h"))
 
 	config.LOG_PATH = cast.ToString(coalesce("LOG_PATH", "logs/info.log"))
 
+	config.TrashRetentionDays = cast.ToInt(coalesce("TRASH_RETENTION_DAYS", 30))
+	config.TrashPurgeInterval = cast.ToDuration(coalesce("TRASH_PURGE_INTERVAL", "1h"))
+
 	return config
 }
 
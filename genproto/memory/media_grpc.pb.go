@@ -19,18 +19,28 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	MediaService_GetMediaById_FullMethodName = "/memory.MediaService/GetMediaById"
-	MediaService_DeleteMedia_FullMethodName  = "/memory.MediaService/DeleteMedia"
-	MediaService_GetAllMedia_FullMethodName  = "/memory.MediaService/GetAllMedia"
+	MediaService_CreateMedia_FullMethodName     = "/memory.MediaService/CreateMedia"
+	MediaService_UpdateMedia_FullMethodName     = "/memory.MediaService/UpdateMedia"
+	MediaService_PatchMedia_FullMethodName      = "/memory.MediaService/PatchMedia"
+	MediaService_GetMediaById_FullMethodName    = "/memory.MediaService/GetMediaById"
+	MediaService_DeleteMedia_FullMethodName     = "/memory.MediaService/DeleteMedia"
+	MediaService_GetAllMedia_FullMethodName     = "/memory.MediaService/GetAllMedia"
+	MediaService_GetDeletedMedia_FullMethodName = "/memory.MediaService/GetDeletedMedia"
+	MediaService_RestoreMedia_FullMethodName    = "/memory.MediaService/RestoreMedia"
 )
 
 // MediaServiceClient is the client API for MediaService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MediaServiceClient interface {
+	CreateMedia(ctx context.Context, in *CreateMediaRequest, opts ...grpc.CallOption) (*Media, error)
+	UpdateMedia(ctx context.Context, in *UpdateMediaRequest, opts ...grpc.CallOption) (*Media, error)
+	PatchMedia(ctx context.Context, in *PatchMediaRequest, opts ...grpc.CallOption) (*Media, error)
 	GetMediaById(ctx context.Context, in *GetMediaByIdRequest, opts ...grpc.CallOption) (*Media, error)
 	DeleteMedia(ctx context.Context, in *DeleteMediaRequest, opts ...grpc.CallOption) (*DeleteMediaResponse, error)
 	GetAllMedia(ctx context.Context, in *GetAllMediaRequest, opts ...grpc.CallOption) (*GetAllMediaResponse, error)
+	GetDeletedMedia(ctx context.Context, in *GetDeletedMediaRequest, opts ...grpc.CallOption) (*GetAllMediaResponse, error)
+	RestoreMedia(ctx context.Context, in *RestoreMediaRequest, opts ...grpc.CallOption) (*Media, error)
 }
 
 type mediaServiceClient struct {
@@ -41,6 +51,36 @@ func NewMediaServiceClient(cc grpc.ClientConnInterface) MediaServiceClient {
 	return &mediaServiceClient{cc}
 }
 
+func (c *mediaServiceClient) CreateMedia(ctx context.Context, in *CreateMediaRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_CreateMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) UpdateMedia(ctx context.Context, in *UpdateMediaRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_UpdateMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) PatchMedia(ctx context.Context, in *PatchMediaRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_PatchMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mediaServiceClient) GetMediaById(ctx context.Context, in *GetMediaByIdRequest, opts ...grpc.CallOption) (*Media, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Media)
@@ -71,13 +111,38 @@ func (c *mediaServiceClient) GetAllMedia(ctx context.Context, in *GetAllMediaReq
 	return out, nil
 }
 
+func (c *mediaServiceClient) GetDeletedMedia(ctx context.Context, in *GetDeletedMediaRequest, opts ...grpc.CallOption) (*GetAllMediaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAllMediaResponse)
+	err := c.cc.Invoke(ctx, MediaService_GetDeletedMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) RestoreMedia(ctx context.Context, in *RestoreMediaRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_RestoreMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MediaServiceServer is the server API for MediaService service.
 // All implementations must embed UnimplementedMediaServiceServer
 // for forward compatibility
 type MediaServiceServer interface {
+	CreateMedia(context.Context, *CreateMediaRequest) (*Media, error)
+	UpdateMedia(context.Context, *UpdateMediaRequest) (*Media, error)
+	PatchMedia(context.Context, *PatchMediaRequest) (*Media, error)
 	GetMediaById(context.Context, *GetMediaByIdRequest) (*Media, error)
 	DeleteMedia(context.Context, *DeleteMediaRequest) (*DeleteMediaResponse, error)
 	GetAllMedia(context.Context, *GetAllMediaRequest) (*GetAllMediaResponse, error)
+	GetDeletedMedia(context.Context, *GetDeletedMediaRequest) (*GetAllMediaResponse, error)
+	RestoreMedia(context.Context, *RestoreMediaRequest) (*Media, error)
 	mustEmbedUnimplementedMediaServiceServer()
 }
 
@@ -85,6 +150,15 @@ type MediaServiceServer interface {
 type UnimplementedMediaServiceServer struct {
 }
 
+func (UnimplementedMediaServiceServer) CreateMedia(context.Context, *CreateMediaRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) UpdateMedia(context.Context, *UpdateMediaRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) PatchMedia(context.Context, *PatchMediaRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PatchMedia not implemented")
+}
 func (UnimplementedMediaServiceServer) GetMediaById(context.Context, *GetMediaByIdRequest) (*Media, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMediaById not implemented")
 }
@@ -94,6 +168,12 @@ func (UnimplementedMediaServiceServer) DeleteMedia(context.Context, *DeleteMedia
 func (UnimplementedMediaServiceServer) GetAllMedia(context.Context, *GetAllMediaRequest) (*GetAllMediaResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllMedia not implemented")
 }
+func (UnimplementedMediaServiceServer) GetDeletedMedia(context.Context, *GetDeletedMediaRequest) (*GetAllMediaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeletedMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) RestoreMedia(context.Context, *RestoreMediaRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMedia not implemented")
+}
 func (UnimplementedMediaServiceServer) mustEmbedUnimplementedMediaServiceServer() {}
 
 // UnsafeMediaServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -107,6 +187,60 @@ func RegisterMediaServiceServer(s grpc.ServiceRegistrar, srv MediaServiceServer)
 	s.RegisterService(&MediaService_ServiceDesc, srv)
 }
 
+func _MediaService_CreateMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).CreateMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_CreateMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).CreateMedia(ctx, req.(*CreateMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_UpdateMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).UpdateMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_UpdateMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).UpdateMedia(ctx, req.(*UpdateMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_PatchMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).PatchMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_PatchMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).PatchMedia(ctx, req.(*PatchMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MediaService_GetMediaById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetMediaByIdRequest)
 	if err := dec(in); err != nil {
@@ -161,6 +295,42 @@ func _MediaService_GetAllMedia_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MediaService_GetDeletedMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeletedMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).GetDeletedMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_GetDeletedMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).GetDeletedMedia(ctx, req.(*GetDeletedMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_RestoreMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).RestoreMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_RestoreMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).RestoreMedia(ctx, req.(*RestoreMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MediaService_ServiceDesc is the grpc.ServiceDesc for MediaService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -168,6 +338,18 @@ var MediaService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "memory.MediaService",
 	HandlerType: (*MediaServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateMedia",
+			Handler:    _MediaService_CreateMedia_Handler,
+		},
+		{
+			MethodName: "UpdateMedia",
+			Handler:    _MediaService_UpdateMedia_Handler,
+		},
+		{
+			MethodName: "PatchMedia",
+			Handler:    _MediaService_PatchMedia_Handler,
+		},
 		{
 			MethodName: "GetMediaById",
 			Handler:    _MediaService_GetMediaById_Handler,
@@ -180,6 +362,14 @@ var MediaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAllMedia",
 			Handler:    _MediaService_GetAllMedia_Handler,
 		},
+		{
+			MethodName: "GetDeletedMedia",
+			Handler:    _MediaService_GetDeletedMedia_Handler,
+		},
+		{
+			MethodName: "RestoreMedia",
+			Handler:    _MediaService_RestoreMedia_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "submodule-for-timecapsule/memory_service/media.proto",
@@ -19,18 +19,34 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	MemoryService_GetMemoryById_FullMethodName  = "/memory.MemoryService/GetMemoryById"
-	MemoryService_DeleteMemory_FullMethodName   = "/memory.MemoryService/DeleteMemory"
-	MemoryService_GetAllMemories_FullMethodName = "/memory.MemoryService/GetAllMemories"
+	MemoryService_CreateMemory_FullMethodName          = "/memory.MemoryService/CreateMemory"
+	MemoryService_UpdateMemory_FullMethodName          = "/memory.MemoryService/UpdateMemory"
+	MemoryService_PatchMemory_FullMethodName           = "/memory.MemoryService/PatchMemory"
+	MemoryService_GetMemoryById_FullMethodName         = "/memory.MemoryService/GetMemoryById"
+	MemoryService_DeleteMemory_FullMethodName          = "/memory.MemoryService/DeleteMemory"
+	MemoryService_GetAllMemories_FullMethodName        = "/memory.MemoryService/GetAllMemories"
+	MemoryService_GetDeletedMemories_FullMethodName    = "/memory.MemoryService/GetDeletedMemories"
+	MemoryService_RestoreMemory_FullMethodName         = "/memory.MemoryService/RestoreMemory"
+	MemoryService_ListMemoryRevisions_FullMethodName   = "/memory.MemoryService/ListMemoryRevisions"
+	MemoryService_GetMemoryAsOf_FullMethodName         = "/memory.MemoryService/GetMemoryAsOf"
+	MemoryService_RestoreMemoryRevision_FullMethodName = "/memory.MemoryService/RestoreMemoryRevision"
 )
 
 // MemoryServiceClient is the client API for MemoryService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MemoryServiceClient interface {
+	CreateMemory(ctx context.Context, in *CreateMemoryRequest, opts ...grpc.CallOption) (*Memory, error)
+	UpdateMemory(ctx context.Context, in *UpdateMemoryRequest, opts ...grpc.CallOption) (*Memory, error)
+	PatchMemory(ctx context.Context, in *PatchMemoryRequest, opts ...grpc.CallOption) (*Memory, error)
 	GetMemoryById(ctx context.Context, in *GetMemoryByIdRequest, opts ...grpc.CallOption) (*Memory, error)
 	DeleteMemory(ctx context.Context, in *DeleteMemoryRequest, opts ...grpc.CallOption) (*DeleteMemoryResponse, error)
 	GetAllMemories(ctx context.Context, in *GetAllMemoriesRequest, opts ...grpc.CallOption) (*GetAllMemoriesResponse, error)
+	GetDeletedMemories(ctx context.Context, in *GetDeletedMemoriesRequest, opts ...grpc.CallOption) (*GetAllMemoriesResponse, error)
+	RestoreMemory(ctx context.Context, in *RestoreMemoryRequest, opts ...grpc.CallOption) (*Memory, error)
+	ListMemoryRevisions(ctx context.Context, in *ListMemoryRevisionsRequest, opts ...grpc.CallOption) (*ListMemoryRevisionsResponse, error)
+	GetMemoryAsOf(ctx context.Context, in *GetMemoryAsOfRequest, opts ...grpc.CallOption) (*Memory, error)
+	RestoreMemoryRevision(ctx context.Context, in *RestoreMemoryRevisionRequest, opts ...grpc.CallOption) (*Memory, error)
 }
 
 type memoryServiceClient struct {
@@ -41,6 +57,36 @@ func NewMemoryServiceClient(cc grpc.ClientConnInterface) MemoryServiceClient {
 	return &memoryServiceClient{cc}
 }
 
+func (c *memoryServiceClient) CreateMemory(ctx context.Context, in *CreateMemoryRequest, opts ...grpc.CallOption) (*Memory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Memory)
+	err := c.cc.Invoke(ctx, MemoryService_CreateMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoryServiceClient) UpdateMemory(ctx context.Context, in *UpdateMemoryRequest, opts ...grpc.CallOption) (*Memory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Memory)
+	err := c.cc.Invoke(ctx, MemoryService_UpdateMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoryServiceClient) PatchMemory(ctx context.Context, in *PatchMemoryRequest, opts ...grpc.CallOption) (*Memory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Memory)
+	err := c.cc.Invoke(ctx, MemoryService_PatchMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *memoryServiceClient) GetMemoryById(ctx context.Context, in *GetMemoryByIdRequest, opts ...grpc.CallOption) (*Memory, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Memory)
@@ -71,13 +117,71 @@ func (c *memoryServiceClient) GetAllMemories(ctx context.Context, in *GetAllMemo
 	return out, nil
 }
 
+func (c *memoryServiceClient) GetDeletedMemories(ctx context.Context, in *GetDeletedMemoriesRequest, opts ...grpc.CallOption) (*GetAllMemoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAllMemoriesResponse)
+	err := c.cc.Invoke(ctx, MemoryService_GetDeletedMemories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoryServiceClient) RestoreMemory(ctx context.Context, in *RestoreMemoryRequest, opts ...grpc.CallOption) (*Memory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Memory)
+	err := c.cc.Invoke(ctx, MemoryService_RestoreMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoryServiceClient) ListMemoryRevisions(ctx context.Context, in *ListMemoryRevisionsRequest, opts ...grpc.CallOption) (*ListMemoryRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMemoryRevisionsResponse)
+	err := c.cc.Invoke(ctx, MemoryService_ListMemoryRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoryServiceClient) GetMemoryAsOf(ctx context.Context, in *GetMemoryAsOfRequest, opts ...grpc.CallOption) (*Memory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Memory)
+	err := c.cc.Invoke(ctx, MemoryService_GetMemoryAsOf_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoryServiceClient) RestoreMemoryRevision(ctx context.Context, in *RestoreMemoryRevisionRequest, opts ...grpc.CallOption) (*Memory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Memory)
+	err := c.cc.Invoke(ctx, MemoryService_RestoreMemoryRevision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MemoryServiceServer is the server API for MemoryService service.
 // All implementations must embed UnimplementedMemoryServiceServer
 // for forward compatibility
 type MemoryServiceServer interface {
+	CreateMemory(context.Context, *CreateMemoryRequest) (*Memory, error)
+	UpdateMemory(context.Context, *UpdateMemoryRequest) (*Memory, error)
+	PatchMemory(context.Context, *PatchMemoryRequest) (*Memory, error)
 	GetMemoryById(context.Context, *GetMemoryByIdRequest) (*Memory, error)
 	DeleteMemory(context.Context, *DeleteMemoryRequest) (*DeleteMemoryResponse, error)
 	GetAllMemories(context.Context, *GetAllMemoriesRequest) (*GetAllMemoriesResponse, error)
+	GetDeletedMemories(context.Context, *GetDeletedMemoriesRequest) (*GetAllMemoriesResponse, error)
+	RestoreMemory(context.Context, *RestoreMemoryRequest) (*Memory, error)
+	ListMemoryRevisions(context.Context, *ListMemoryRevisionsRequest) (*ListMemoryRevisionsResponse, error)
+	GetMemoryAsOf(context.Context, *GetMemoryAsOfRequest) (*Memory, error)
+	RestoreMemoryRevision(context.Context, *RestoreMemoryRevisionRequest) (*Memory, error)
 	mustEmbedUnimplementedMemoryServiceServer()
 }
 
@@ -85,6 +189,15 @@ type MemoryServiceServer interface {
 type UnimplementedMemoryServiceServer struct {
 }
 
+func (UnimplementedMemoryServiceServer) CreateMemory(context.Context, *CreateMemoryRequest) (*Memory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMemory not implemented")
+}
+func (UnimplementedMemoryServiceServer) UpdateMemory(context.Context, *UpdateMemoryRequest) (*Memory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMemory not implemented")
+}
+func (UnimplementedMemoryServiceServer) PatchMemory(context.Context, *PatchMemoryRequest) (*Memory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PatchMemory not implemented")
+}
 func (UnimplementedMemoryServiceServer) GetMemoryById(context.Context, *GetMemoryByIdRequest) (*Memory, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMemoryById not implemented")
 }
@@ -94,6 +207,21 @@ func (UnimplementedMemoryServiceServer) DeleteMemory(context.Context, *DeleteMem
 func (UnimplementedMemoryServiceServer) GetAllMemories(context.Context, *GetAllMemoriesRequest) (*GetAllMemoriesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllMemories not implemented")
 }
+func (UnimplementedMemoryServiceServer) GetDeletedMemories(context.Context, *GetDeletedMemoriesRequest) (*GetAllMemoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeletedMemories not implemented")
+}
+func (UnimplementedMemoryServiceServer) RestoreMemory(context.Context, *RestoreMemoryRequest) (*Memory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMemory not implemented")
+}
+func (UnimplementedMemoryServiceServer) ListMemoryRevisions(context.Context, *ListMemoryRevisionsRequest) (*ListMemoryRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMemoryRevisions not implemented")
+}
+func (UnimplementedMemoryServiceServer) GetMemoryAsOf(context.Context, *GetMemoryAsOfRequest) (*Memory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemoryAsOf not implemented")
+}
+func (UnimplementedMemoryServiceServer) RestoreMemoryRevision(context.Context, *RestoreMemoryRevisionRequest) (*Memory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMemoryRevision not implemented")
+}
 func (UnimplementedMemoryServiceServer) mustEmbedUnimplementedMemoryServiceServer() {}
 
 // UnsafeMemoryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -107,6 +235,60 @@ func RegisterMemoryServiceServer(s grpc.ServiceRegistrar, srv MemoryServiceServe
 	s.RegisterService(&MemoryService_ServiceDesc, srv)
 }
 
+func _MemoryService_CreateMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).CreateMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_CreateMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).CreateMemory(ctx, req.(*CreateMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoryService_UpdateMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).UpdateMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_UpdateMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).UpdateMemory(ctx, req.(*UpdateMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoryService_PatchMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).PatchMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_PatchMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).PatchMemory(ctx, req.(*PatchMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MemoryService_GetMemoryById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetMemoryByIdRequest)
 	if err := dec(in); err != nil {
@@ -161,6 +343,96 @@ func _MemoryService_GetAllMemories_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MemoryService_GetDeletedMemories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeletedMemoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).GetDeletedMemories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_GetDeletedMemories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).GetDeletedMemories(ctx, req.(*GetDeletedMemoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoryService_RestoreMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).RestoreMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_RestoreMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).RestoreMemory(ctx, req.(*RestoreMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoryService_ListMemoryRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMemoryRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).ListMemoryRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_ListMemoryRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).ListMemoryRevisions(ctx, req.(*ListMemoryRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoryService_GetMemoryAsOf_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemoryAsOfRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).GetMemoryAsOf(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_GetMemoryAsOf_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).GetMemoryAsOf(ctx, req.(*GetMemoryAsOfRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoryService_RestoreMemoryRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMemoryRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).RestoreMemoryRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_RestoreMemoryRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).RestoreMemoryRevision(ctx, req.(*RestoreMemoryRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MemoryService_ServiceDesc is the grpc.ServiceDesc for MemoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -168,6 +440,18 @@ var MemoryService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "memory.MemoryService",
 	HandlerType: (*MemoryServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateMemory",
+			Handler:    _MemoryService_CreateMemory_Handler,
+		},
+		{
+			MethodName: "UpdateMemory",
+			Handler:    _MemoryService_UpdateMemory_Handler,
+		},
+		{
+			MethodName: "PatchMemory",
+			Handler:    _MemoryService_PatchMemory_Handler,
+		},
 		{
 			MethodName: "GetMemoryById",
 			Handler:    _MemoryService_GetMemoryById_Handler,
@@ -180,6 +464,26 @@ var MemoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAllMemories",
 			Handler:    _MemoryService_GetAllMemories_Handler,
 		},
+		{
+			MethodName: "GetDeletedMemories",
+			Handler:    _MemoryService_GetDeletedMemories_Handler,
+		},
+		{
+			MethodName: "RestoreMemory",
+			Handler:    _MemoryService_RestoreMemory_Handler,
+		},
+		{
+			MethodName: "ListMemoryRevisions",
+			Handler:    _MemoryService_ListMemoryRevisions_Handler,
+		},
+		{
+			MethodName: "GetMemoryAsOf",
+			Handler:    _MemoryService_GetMemoryAsOf_Handler,
+		},
+		{
+			MethodName: "RestoreMemoryRevision",
+			Handler:    _MemoryService_RestoreMemoryRevision_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "submodule-for-timecapsule/memory_service/memory.proto",
@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"fmt"
+	"math"
+)
+
+// EarthRadiusMeters is the mean Earth radius used for distance calculations.
+const EarthRadiusMeters = 6371000.0
+
+// metersPerDegree is the length of one degree of latitude.
+const metersPerDegree = math.Pi * EarthRadiusMeters / 180
+
+// HaversineDistance returns the great-circle distance in meters between two
+// points given in decimal degrees.
+func HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox returns a box that contains every point within radius meters
+// of the center. It is used to pre-filter candidates before the exact
+// distance check. ok is false when the box would span all longitudes (near
+// the poles or for huge radii), in which case only latitude bounds apply.
+func BoundingBox(lat, lon, radius float64) (minLat, minLon, maxLat, maxLon float64, ok bool) {
+	dLat := radius / metersPerDegree
+	minLat = math.Max(-90, lat-dLat)
+	maxLat = math.Min(90, lat+dLat)
+
+	cosLat := math.Cos(toRadians(math.Max(math.Abs(minLat), math.Abs(maxLat))))
+	if cosLat < 1e-6 {
+		return minLat, -180, maxLat, 180, false
+	}
+	dLon := dLat / cosLat
+	if dLon >= 180 {
+		return minLat, -180, maxLat, 180, false
+	}
+
+	return minLat, NormalizeLongitude(lon - dLon), maxLat, NormalizeLongitude(lon + dLon), true
+}
+
+// NormalizeLongitude wraps a longitude into the [-180, 180] range.
+func NormalizeLongitude(lon float64) float64 {
+	for lon > 180 {
+		lon -= 360
+	}
+	for lon < -180 {
+		lon += 360
+	}
+	return lon
+}
+
+// ValidateCoordinates reports whether lat/lon are within valid ranges.
+func ValidateCoordinates(lat, lon float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %v out of range [-90, 90]", lat)
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude %v out of range [-180, 180]", lon)
+	}
+	return nil
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -11,3 +11,30 @@ func DateToString(date sql.NullTime) string {
 	}
 	return ""
 }
+
+const (
+	// DefaultLimit is the page size used when a request does not specify one.
+	DefaultLimit = 10
+	// MaxLimit caps the page size a client may request.
+	MaxLimit = 100
+)
+
+// Pagination normalises the page and limit of a list request and returns the
+// resulting LIMIT and OFFSET values. Pages are 1-based.
+func Pagination(page, limit int32) (int32, int32) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	if page <= 0 {
+		page = 1
+	}
+	return limit, (page - 1) * limit
+}
+
+// Ptr returns a pointer to v, handy for optional model fields.
+func Ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,52 @@
+package consumer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/time_capsule/memory-service/kafka/consumer"
+	"github.com/time_capsule/memory-service/storage/inmemory"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	brokers := []string{"localhost:9092"}
+	topic := "test-dlq-source-topic"
+	dlqTopic := "test-dlq-topic"
+	createTopic(t, brokers, topic)
+	defer deleteTopic(t, brokers, topic)
+	createTopic(t, brokers, dlqTopic)
+	defer deleteTopic(t, brokers, dlqTopic)
+
+	// A memory without a title fails validation, an unknown operation is never
+	// processed; both end up in the dead-letter topic.
+	produceMessage(t, brokers, topic, "memory.create", map[string]interface{}{"privacy": "public"})
+	produceMessage(t, brokers, topic, "memory.archive", map[string]interface{}{})
+
+	dlq := consumer.NewDeadLetterQueue(brokers, dlqTopic)
+	defer dlq.Close()
+	c := consumer.NewMemoryConsumer(brokers, topic, inmemory.NewInMemoryStorage(), consumer.Options{DeadLetter: dlq})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Consume(ctx)
+
+	// Wait for the messages to be consumed (adjust timeout as needed)
+	time.Sleep(time.Second * 5)
+
+	msgs, err := dlq.List(context.Background(), 0)
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 2) {
+		assert.Equal(t, "memory.create", string(msgs[0].Key))
+		assert.Equal(t, topic, consumer.Header(msgs[0], consumer.HeaderTopic))
+		assert.Equal(t, "0", consumer.Header(msgs[0], consumer.HeaderOffset))
+		assert.Equal(t, "1", consumer.Header(msgs[0], consumer.HeaderAttempts))
+		assert.Contains(t, consumer.Header(msgs[0], consumer.HeaderError), "title")
+		assert.Contains(t, consumer.Header(msgs[1], consumer.HeaderError), "unknown operation")
+	}
+
+	redriven, err := dlq.Redrive(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, redriven)
+}
@@ -9,24 +9,22 @@ import (
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
-	"github.com/time_capsule/memory-service/config"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
 	"github.com/time_capsule/memory-service/kafka/consumer"
 	"github.com/time_capsule/memory-service/models"
-	"github.com/time_capsule/memory-service/storage/test"
+	"github.com/time_capsule/memory-service/storage/inmemory"
 )
 
 func TestMemoryConsumer(t *testing.T) {
-	cfg := config.Load()
-
 	// Create a test topic
 	topic := "test-memory-topic"
 	createTopic(t, []string{"localhost:9092"}, topic)
 	defer deleteTopic(t, []string{"localhost:9092"}, topic)
 
-	storage, err := test.NewPostgresStorageTest(cfg)
-	if err != nil {
-		t.Fatalf("failed to initialize storage: %v", err)
-	}
+	// The consumer is what is under test; an in-process store keeps the test
+	// independent of a database.
+	storage := inmemory.NewInMemoryStorage()
 
 	// Create a test memory model
 	memoryModel := &models.CreateMemoryModel{
@@ -36,8 +34,8 @@ func TestMemoryConsumer(t *testing.T) {
 		Description: "This is a test memory.",
 		Date:        time.Now(),
 		Tags:        []string{"test", "memory"},
-		Latitude:    34.0522,
-		Longitude:   -118.2437,
+		Latitude:    helper.Ptr(34.0522),
+		Longitude:   helper.Ptr(-118.2437),
 		PlaceName:   "Los Angeles",
 		Privacy:     "public",
 	}
@@ -46,7 +44,7 @@ func TestMemoryConsumer(t *testing.T) {
 	produceMessage(t, []string{"localhost:9092"}, topic, "memory.create", memoryModel)
 
 	// Create a MemoryConsumer with the actual storage
-	consumer := consumer.NewMemoryConsumer([]string{"localhost:9092"}, topic, storage)
+	consumer := consumer.NewMemoryConsumer([]string{"localhost:9092"}, topic, storage, consumer.Options{})
 
 	// Consume the message
 	go func() {
@@ -69,6 +67,246 @@ func TestMemoryConsumer(t *testing.T) {
 	assert.Equal(t, memoryModel.Description, createdMemory.Description)
 }
 
+func TestMemoryConsumerDelete(t *testing.T) {
+	topic := "test-memory-delete-topic"
+	createTopic(t, []string{"localhost:9092"}, topic)
+	defer deleteTopic(t, []string{"localhost:9092"}, topic)
+
+	storage := inmemory.NewInMemoryStorage()
+	userID := uuid.NewString()
+	var memoryIDs []string
+	for _, title := range []string{"First", "Second", "Third"} {
+		id, err := storage.Memory().CreateMemory(context.Background(), &models.CreateMemoryModel{
+			UserID:  userID,
+			Title:   title,
+			Date:    time.Now(),
+			Privacy: "private",
+		})
+		assert.NoError(t, err)
+		memoryIDs = append(memoryIDs, id)
+	}
+	mediaID, err := storage.Media().CreateMedia(context.Background(), &models.CreateMediaModel{
+		MemoryID: memoryIDs[0],
+		Type:     "image",
+		URL:      "https://example.com/image.jpg",
+	})
+	assert.NoError(t, err)
+
+	// Delete one memory, then the rest by user.
+	produceMessage(t, []string{"localhost:9092"}, topic, "memory.delete", map[string]string{"id": memoryIDs[0]})
+	produceMessage(t, []string{"localhost:9092"}, topic, "memory.delete_by_user", map[string]string{"user_id": userID})
+
+	consumer := consumer.NewMemoryConsumer([]string{"localhost:9092"}, topic, storage, consumer.Options{})
+	go func() {
+		if err := consumer.Consume(context.Background()); err != nil {
+			t.Errorf("Error consuming message: %v", err)
+		}
+	}()
+
+	// Wait for the messages to be consumed (adjust timeout as needed)
+	time.Sleep(time.Second * 2)
+
+	for _, id := range memoryIDs {
+		_, err := storage.Memory().GetMemoryByID(context.Background(), id)
+		assert.Error(t, err)
+	}
+	// Media are deleted with their memory, as with the DeleteMemory RPC.
+	_, err = storage.Media().GetMediaByID(context.Background(), mediaID)
+	assert.Error(t, err)
+}
+
+func TestMemoryConsumerEnvelope(t *testing.T) {
+	topic := "test-memory-envelope-topic"
+	createTopic(t, []string{"localhost:9092"}, topic)
+	defer deleteTopic(t, []string{"localhost:9092"}, topic)
+
+	storage := inmemory.NewInMemoryStorage()
+	id, err := storage.Memory().CreateMemory(context.Background(), &models.CreateMemoryModel{
+		UserID:      uuid.NewString(),
+		Title:       "Old title",
+		Description: "Old description",
+		Date:        time.Now(),
+		Privacy:     "private",
+	})
+	assert.NoError(t, err)
+
+	// Patch the title and reset the description; the message key no longer
+	// names the operation.
+	payload, err := json.Marshal(consumer.MemoryPatch{
+		PatchMemoryModel: models.PatchMemoryModel{ID: id, Title: helper.Ptr("New title")},
+		Fields:           []string{"title", "description"},
+	})
+	assert.NoError(t, err)
+	produceMessage(t, []string{"localhost:9092"}, topic, id, consumer.Envelope{
+		CommandID:     uuid.NewString(),
+		Operation:     "memory.patch",
+		SchemaVersion: consumer.SchemaVersion,
+		Actor:         uuid.NewString(),
+		Timestamp:     time.Now(),
+		Payload:       payload,
+	})
+
+	consumer := consumer.NewMemoryConsumer([]string{"localhost:9092"}, topic, storage, consumer.Options{})
+	go func() {
+		if err := consumer.Consume(context.Background()); err != nil {
+			t.Errorf("Error consuming message: %v", err)
+		}
+	}()
+
+	// Wait for the message to be consumed (adjust timeout as needed)
+	time.Sleep(time.Second * 2)
+
+	patched, err := storage.Memory().GetMemoryByID(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, "New title", patched.Title)
+	assert.Empty(t, patched.Description)
+}
+
+func TestMemoryConsumerOrdering(t *testing.T) {
+	topic := "test-memory-ordering-topic"
+	createTopic(t, []string{"localhost:9092"}, topic)
+	defer deleteTopic(t, []string{"localhost:9092"}, topic)
+
+	storage := inmemory.NewInMemoryStorage()
+
+	// Create and patch several memories, keyed by memory id. Each patch
+	// must be applied after the create of its memory.
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id := uuid.NewString()
+		ids = append(ids, id)
+		create, err := json.Marshal(models.CreateMemoryModel{
+			ID:      id,
+			UserID:  uuid.NewString(),
+			Title:   "Created",
+			Date:    time.Now(),
+			Privacy: "public",
+		})
+		assert.NoError(t, err)
+		patch, err := json.Marshal(consumer.MemoryPatch{
+			PatchMemoryModel: models.PatchMemoryModel{ID: id, Title: helper.Ptr("Patched")},
+		})
+		assert.NoError(t, err)
+		for _, cmd := range []struct {
+			operation string
+			payload   []byte
+		}{{"memory.create", create}, {"memory.patch", patch}} {
+			produceMessage(t, []string{"localhost:9092"}, topic, id, consumer.Envelope{
+				CommandID:     uuid.NewString(),
+				Operation:     cmd.operation,
+				SchemaVersion: consumer.SchemaVersion,
+				Timestamp:     time.Now(),
+				Payload:       cmd.payload,
+			})
+		}
+	}
+
+	consumer := consumer.NewMemoryConsumer([]string{"localhost:9092"}, topic, storage, consumer.Options{Concurrency: 4})
+	go func() {
+		if err := consumer.Consume(context.Background()); err != nil {
+			t.Errorf("Error consuming message: %v", err)
+		}
+	}()
+
+	// Wait for the messages to be consumed (adjust timeout as needed)
+	time.Sleep(time.Second * 2)
+
+	for _, id := range ids {
+		m, err := storage.Memory().GetMemoryByID(context.Background(), id)
+		assert.NoError(t, err)
+		if m != nil {
+			assert.Equal(t, "Patched", m.Title)
+		}
+	}
+}
+
+func TestMemoryConsumerDeduplicate(t *testing.T) {
+	topic := "test-memory-dedupe-topic"
+	createTopic(t, []string{"localhost:9092"}, topic)
+	defer deleteTopic(t, []string{"localhost:9092"}, topic)
+
+	storage := inmemory.NewInMemoryStorage()
+
+	// The same command twice, as after a crash before the offset was
+	// committed. Without an id in the payload a second create would add a
+	// second memory.
+	userID := uuid.NewString()
+	payload, err := json.Marshal(models.CreateMemoryModel{
+		UserID:  userID,
+		Title:   "Once",
+		Date:    time.Now(),
+		Privacy: "public",
+	})
+	assert.NoError(t, err)
+	envelope := consumer.Envelope{
+		CommandID:     uuid.NewString(),
+		Operation:     "memory.create",
+		SchemaVersion: consumer.SchemaVersion,
+		Timestamp:     time.Now(),
+		Payload:       payload,
+	}
+	produceMessage(t, []string{"localhost:9092"}, topic, userID, envelope)
+	produceMessage(t, []string{"localhost:9092"}, topic, userID, envelope)
+
+	consumer := consumer.NewMemoryConsumer([]string{"localhost:9092"}, topic, storage, consumer.Options{Deduplicate: true})
+	go func() {
+		if err := consumer.Consume(context.Background()); err != nil {
+			t.Errorf("Error consuming message: %v", err)
+		}
+	}()
+
+	// Wait for the messages to be consumed (adjust timeout as needed)
+	time.Sleep(time.Second * 2)
+
+	memories, err := storage.Memory().GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{UserId: userID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, memories.Memories, 1)
+}
+
+func TestMemoryConsumerBatch(t *testing.T) {
+	topic := "test-memory-batch-topic"
+	createTopic(t, []string{"localhost:9092"}, topic)
+	defer deleteTopic(t, []string{"localhost:9092"}, topic)
+
+	storage := inmemory.NewInMemoryStorage()
+
+	// A run of creates with an invalid one among them. Whether they are
+	// batched depends on how fast they are fetched (the bulk path itself is
+	// covered by the consumer package tests); either way only the valid
+	// creates are written.
+	userID := uuid.NewString()
+	for _, title := range []string{"One", "Two", "", "Three", "Four"} {
+		payload, err := json.Marshal(models.CreateMemoryModel{
+			UserID:  userID,
+			Title:   title,
+			Date:    time.Now(),
+			Privacy: "public",
+		})
+		assert.NoError(t, err)
+		produceMessage(t, []string{"localhost:9092"}, topic, userID, consumer.Envelope{
+			CommandID:     uuid.NewString(),
+			Operation:     "memory.create",
+			SchemaVersion: consumer.SchemaVersion,
+			Timestamp:     time.Now(),
+			Payload:       payload,
+		})
+	}
+
+	consumer := consumer.NewMemoryConsumer([]string{"localhost:9092"}, topic, storage, consumer.Options{Deduplicate: true, BatchSize: 10})
+	go func() {
+		if err := consumer.Consume(context.Background()); err != nil {
+			t.Errorf("Error consuming message: %v", err)
+		}
+	}()
+
+	// Wait for the messages to be consumed (adjust timeout as needed)
+	time.Sleep(time.Second * 2)
+
+	memories, err := storage.Memory().GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{UserId: userID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, memories.Memories, 4)
+}
+
 // Helper functions to create, delete, and produce messages to a Kafka topic
 func createTopic(t *testing.T, brokers []string, topic string) {
 	conn, err := kafka.DialLeader(context.Background(), "tcp", brokers[0], topic, 0)
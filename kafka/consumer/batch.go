@@ -0,0 +1,90 @@
+package consumer
+
+import (
+	"context"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// run is a sequence of consecutive messages of a batch. The messages of a
+// run with a bulk handler carry commands with the same operation and are
+// processed at once; the messages of other runs one at a time.
+type run struct {
+	msgs      []kafka.Message
+	envelopes []*Envelope
+	handle    bulkHandlerFunc
+}
+
+// splitRuns splits batch into runs, keeping the order of its messages.
+// Consecutive messages whose operation has a handler in bulk form a run of
+// their own; a lone such message and messages that cannot be decoded are
+// processed one at a time like every other message.
+func splitRuns(batch []kafka.Message, bulk map[string]bulkHandlerFunc) []*run {
+	var (
+		runs []*run
+		last *run
+	)
+	for _, msg := range batch {
+		var (
+			envelope *Envelope
+			handle   bulkHandlerFunc
+		)
+		if len(bulk) > 0 && len(batch) > 1 {
+			if decoded, err := decodeEnvelope(msg); err == nil {
+				envelope, handle = decoded, bulk[decoded.Operation]
+			}
+		}
+
+		switch {
+		case handle != nil && last != nil && last.handle != nil && last.envelopes[0].Operation == envelope.Operation:
+			last.msgs = append(last.msgs, msg)
+			last.envelopes = append(last.envelopes, envelope)
+		case handle != nil:
+			last = &run{msgs: []kafka.Message{msg}, envelopes: []*Envelope{envelope}, handle: handle}
+			runs = append(runs, last)
+		case last != nil && last.handle == nil:
+			last.msgs = append(last.msgs, msg)
+		default:
+			last = &run{msgs: []kafka.Message{msg}}
+			runs = append(runs, last)
+		}
+	}
+
+	// A single command gains nothing from a bulk write.
+	for _, r := range runs {
+		if len(r.msgs) == 1 {
+			r.handle = nil
+		}
+	}
+	return runs
+}
+
+// processBatch processes the messages of batch in order and passes each of
+// them to settle with the number of attempts made and the final error. Runs
+// with a bulk handler are processed at once; when that fails, their messages
+// are processed one at a time with handle, retrying as policy allows.
+func processBatch(ctx context.Context, batch []kafka.Message, policy RetryPolicy, handle handlerFunc, bulk map[string]bulkHandlerFunc, settle func(msg kafka.Message, attempts int, err error)) {
+	for _, run := range splitRuns(batch, bulk) {
+		if ctx.Err() != nil {
+			return
+		}
+		if run.handle != nil {
+			err := run.handle(ctx, run.envelopes)
+			if err == nil {
+				for _, msg := range run.msgs {
+					settle(msg, 1, nil)
+				}
+				continue
+			}
+			log.Printf("error processing %d %s commands at once, processing them one at a time: %v", len(run.msgs), run.envelopes[0].Operation, err)
+		}
+		for _, msg := range run.msgs {
+			if ctx.Err() != nil {
+				return
+			}
+			attempts, err := process(ctx, msg, policy, handle)
+			settle(msg, attempts, err)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/storage/inmemory"
+)
+
+// countingStorage is an in-memory storage that counts the memory creates
+// going through it.
+type countingStorage struct {
+	storage.StorageI
+	memories *countingMemories
+}
+
+func (s *countingStorage) Memory() storage.MemoryI {
+	return s.memories
+}
+
+type countingMemories struct {
+	storage.MemoryI
+	creates     int
+	bulkCreates int
+}
+
+func (m *countingMemories) CreateMemory(ctx context.Context, memory *models.CreateMemoryModel) (string, error) {
+	m.creates++
+	return m.MemoryI.CreateMemory(ctx, memory)
+}
+
+func (m *countingMemories) BulkCreateMemories(ctx context.Context, memories []*models.CreateMemoryModel) ([]string, error) {
+	m.bulkCreates++
+	return m.MemoryI.BulkCreateMemories(ctx, memories)
+}
+
+func newCountingStorage() *countingStorage {
+	s := inmemory.NewInMemoryStorage()
+	return &countingStorage{StorageI: s, memories: &countingMemories{MemoryI: s.Memory()}}
+}
+
+// command returns a message carrying a command with the given offset.
+func command(t *testing.T, offset int64, operation string, payload interface{}) kafka.Message {
+	raw, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	value, err := json.Marshal(Envelope{
+		CommandID:     uuid.NewString(),
+		Operation:     operation,
+		SchemaVersion: SchemaVersion,
+		Timestamp:     time.Now(),
+		Payload:       raw,
+	})
+	assert.NoError(t, err)
+	return kafka.Message{Topic: "memory_topic", Offset: offset, Value: value}
+}
+
+func createMemory(t *testing.T, offset int64, userID, title string) kafka.Message {
+	return command(t, offset, "memory.create", models.CreateMemoryModel{
+		UserID:  userID,
+		Title:   title,
+		Date:    time.Now(),
+		Privacy: "public",
+	})
+}
+
+// runBatch processes batch with a memory consumer on s and returns the
+// offsets that were settled and those that failed.
+func runBatch(t *testing.T, s storage.StorageI, batch []kafka.Message) (settled, failed []int64) {
+	c := &MemoryConsumer{storage: s}
+	bulk := map[string]bulkHandlerFunc{"memory.create": c.handleCreates}
+	processBatch(context.Background(), batch, RetryPolicy{}, c.handle, bulk, func(msg kafka.Message, attempts int, err error) {
+		settled = append(settled, msg.Offset)
+		if err != nil {
+			failed = append(failed, msg.Offset)
+		}
+	})
+	return settled, failed
+}
+
+func countMemories(t *testing.T, s storage.StorageI, userID string) int {
+	memories, err := s.Memory().GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{UserId: userID, Limit: 100})
+	assert.NoError(t, err)
+	return len(memories.Memories)
+}
+
+func TestSplitRuns(t *testing.T) {
+	userID := uuid.NewString()
+	bulk := map[string]bulkHandlerFunc{"memory.create": func(context.Context, []*Envelope) error { return nil }}
+	batch := []kafka.Message{
+		createMemory(t, 0, userID, "One"),
+		createMemory(t, 1, userID, "Two"),
+		command(t, 2, "memory.delete", DeletePayload{ID: uuid.NewString()}),
+		{Offset: 3, Value: []byte("not json")},
+		createMemory(t, 4, userID, "Three"),
+		command(t, 5, "memory.delete", DeletePayload{ID: uuid.NewString()}),
+		createMemory(t, 6, userID, "Four"),
+		createMemory(t, 7, userID, "Five"),
+		createMemory(t, 8, userID, "Six"),
+	}
+
+	var (
+		offsets [][]int64
+		isBulk  []bool
+	)
+	for _, r := range splitRuns(batch, bulk) {
+		var runOffsets []int64
+		for _, msg := range r.msgs {
+			runOffsets = append(runOffsets, msg.Offset)
+		}
+		offsets = append(offsets, runOffsets)
+		isBulk = append(isBulk, r.handle != nil)
+	}
+
+	// Every message keeps its place; a lone create is not worth a bulk write.
+	assert.Equal(t, [][]int64{{0, 1}, {2, 3}, {4}, {5}, {6, 7, 8}}, offsets)
+	assert.Equal(t, []bool{true, false, false, false, true}, isBulk)
+}
+
+func TestProcessBatch(t *testing.T) {
+	t.Run("Bulk", func(t *testing.T) {
+		s := newCountingStorage()
+		userID := uuid.NewString()
+		batch := []kafka.Message{
+			createMemory(t, 0, userID, "One"),
+			createMemory(t, 1, userID, "Two"),
+			createMemory(t, 2, userID, "Three"),
+			command(t, 3, "memory.delete", DeletePayload{ID: uuid.NewString()}),
+			createMemory(t, 4, userID, "Four"),
+			createMemory(t, 5, userID, "Five"),
+		}
+
+		settled, failed := runBatch(t, s, batch)
+		assert.Equal(t, []int64{0, 1, 2, 3, 4, 5}, settled)
+		assert.Empty(t, failed)
+		assert.Equal(t, 2, s.memories.bulkCreates)
+		assert.Equal(t, 0, s.memories.creates)
+		assert.Equal(t, 5, countMemories(t, s, userID))
+	})
+
+	t.Run("FallBackToOneAtATime", func(t *testing.T) {
+		s := newCountingStorage()
+		userID := uuid.NewString()
+		batch := []kafka.Message{
+			createMemory(t, 0, userID, "One"),
+			createMemory(t, 1, userID, ""), // Fails validation
+			createMemory(t, 2, userID, "Three"),
+		}
+
+		// The invalid create fails the bulk write before it reaches storage;
+		// the others are then created one at a time.
+		settled, failed := runBatch(t, s, batch)
+		assert.Equal(t, []int64{0, 1, 2}, settled)
+		assert.Equal(t, []int64{1}, failed)
+		assert.Equal(t, 0, s.memories.bulkCreates)
+		assert.Equal(t, 2, s.memories.creates)
+		assert.Equal(t, 2, countMemories(t, s, userID))
+	})
+
+	t.Run("FallBackAfterStorageFailure", func(t *testing.T) {
+		s := newCountingStorage()
+		userID := uuid.NewString()
+		taken, err := s.Memory().CreateMemory(context.Background(), &models.CreateMemoryModel{
+			UserID:  userID,
+			Title:   "Taken",
+			Date:    time.Now(),
+			Privacy: "public",
+		})
+		assert.NoError(t, err)
+		s.memories.creates = 0
+
+		batch := []kafka.Message{
+			createMemory(t, 0, userID, "One"),
+			command(t, 1, "memory.create", models.CreateMemoryModel{
+				ID:      taken,
+				UserID:  userID,
+				Title:   "Duplicate",
+				Date:    time.Now(),
+				Privacy: "public",
+			}),
+			createMemory(t, 2, userID, "Three"),
+		}
+
+		// The bulk insert is rolled back as a whole, so nothing is created
+		// twice when the creates are retried one at a time.
+		settled, failed := runBatch(t, s, batch)
+		assert.Equal(t, []int64{0, 1, 2}, settled)
+		assert.Equal(t, []int64{1}, failed)
+		assert.Equal(t, 1, s.memories.bulkCreates)
+		assert.Equal(t, 3, s.memories.creates)
+		assert.Equal(t, 3, countMemories(t, s, userID))
+	})
+}
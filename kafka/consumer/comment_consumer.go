@@ -2,79 +2,149 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 
 	"github.com/segmentio/kafka-go"
+	"github.com/time_capsule/memory-service/genproto/memory"
 	"github.com/time_capsule/memory-service/models"
 	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
 )
 
 // CommentConsumer consumes Kafka messages related to comments.
 type CommentConsumer struct {
 	reader  *kafka.Reader
 	storage storage.StorageI
+	opts    Options
 }
 
 // NewCommentConsumer creates a new CommentConsumer instance.
-func NewCommentConsumer(kafkaBrokers []string, topic string, storage storage.StorageI) *CommentConsumer {
+func NewCommentConsumer(kafkaBrokers []string, topic string, storage storage.StorageI, opts Options) *CommentConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers: kafkaBrokers,
 		Topic:   topic,
 		GroupID: "comment-group", // Choose a suitable group ID
 	})
-	return &CommentConsumer{reader: reader, storage: storage}
+	return &CommentConsumer{reader: reader, storage: storage, opts: opts}
 }
 
 // Consume starts consuming messages from the Kafka topic.
 func (c *CommentConsumer) Consume(ctx context.Context) error {
-	for {
-		msg, err := c.reader.FetchMessage(ctx)
+	return consume(ctx, c.reader, c.opts, c.handle, map[string]bulkHandlerFunc{
+		"comment.create": c.handleCreates,
+	})
+}
+
+// handle processes a single comment command.
+func (c *CommentConsumer) handle(ctx context.Context, msg kafka.Message) error {
+	envelope, err := decodeEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	return applyOnce(ctx, c.storage, c.opts, envelope, c.apply)
+}
+
+// handleCreates creates the comments of several comment.create commands at once.
+func (c *CommentConsumer) handleCreates(ctx context.Context, envelopes []*Envelope) error {
+	return applyAllOnce(ctx, c.storage, c.opts, envelopes, c.applyCreates)
+}
+
+// applyCreates writes the comments of comment.create commands to s with one
+// bulk insert.
+func (c *CommentConsumer) applyCreates(ctx context.Context, s storage.StorageI, envelopes []*Envelope) error {
+	createModels := make([]*models.CreateCommentModel, len(envelopes))
+	for i, envelope := range envelopes {
+		var createModel models.CreateCommentModel
+		err := envelope.decodePayload(&createModel)
+		if err == nil {
+			err = validation.CreateComment(&createModel)
+		}
 		if err != nil {
-			return fmt.Errorf("error fetching message: %w", err)
+			return fmt.Errorf("error creating comments: %w", err)
 		}
+		createModels[i] = &createModel
+	}
+	if _, err := s.Comment().BulkCreateComments(ctx, createModels); err != nil {
+		return fmt.Errorf("error creating comments: %w", err)
+	}
+	return nil
+}
 
-		var commentModel models.CreateCommentModel // Or the appropriate model for the message
-		if err := json.Unmarshal(msg.Value, &commentModel); err != nil {
-			log.Printf("error unmarshalling message: %v", err)
-			continue // Skip to the next message
+// apply writes the changes of a comment command to s.
+func (c *CommentConsumer) apply(ctx context.Context, s storage.StorageI, envelope *Envelope) error {
+	switch envelope.Operation {
+	case "comment.create":
+		var createModel models.CreateCommentModel
+		err := envelope.decodePayload(&createModel)
+		if err == nil {
+			err = validation.CreateComment(&createModel)
 		}
-
-		// Process the commentModel based on the message type (create, update, patch)
-		switch string(msg.Key) {
-		case "comment.create":
-			if _, err := c.storage.Comment().CreateComment(ctx, &commentModel); err != nil {
-				log.Printf("error creating comment: %v", err)
-			}
-		case "comment.update":
-			updateModel := &models.UpdateCommentModel{
-				ID:       commentModel.ID,
-				MemoryID: commentModel.MemoryID,
-				UserID:   commentModel.UserID,
-				Content:  commentModel.Content,
-				Created:  commentModel.Created,
-			}
-			if err := c.storage.Comment().UpdateComment(ctx, updateModel); err != nil {
-				log.Printf("error updating comment: %v", err)
-			}
-		case "comment.patch":
-			patchModel := &models.PatchCommentModel{
-				ID:       commentModel.ID,
-				MemoryID: &commentModel.MemoryID,
-				UserID:   &commentModel.UserID,
-				Content:  &commentModel.Content,
-				Created:  &commentModel.Created,
-			}
-			if err := c.storage.Comment().PatchComment(ctx, patchModel); err != nil {
-				log.Printf("error patching comment: %v", err)
-			}
-		default:
-			log.Printf("unknown message key: %s", msg.Key)
+		if err == nil {
+			_, err = s.Comment().CreateComment(ctx, &createModel)
 		}
-
-		if err := c.reader.CommitMessages(ctx, msg); err != nil {
-			return fmt.Errorf("error committing message: %w", err)
+		if err != nil {
+			return fmt.Errorf("error creating comment: %w", err)
+		}
+	case "comment.update":
+		var updateModel models.UpdateCommentModel
+		err := envelope.decodePayload(&updateModel)
+		if err == nil {
+			err = validation.UpdateComment(&updateModel)
+		}
+		if err == nil {
+			err = s.Comment().UpdateComment(ctx, &updateModel)
+		}
+		if err != nil {
+			return fmt.Errorf("error updating comment: %w", err)
+		}
+	case "comment.patch":
+		var patch CommentPatch
+		err := envelope.decodePayload(&patch)
+		var patchModel *models.PatchCommentModel
+		if err == nil {
+			patchModel, err = patch.model()
+		}
+		if err == nil {
+			err = validation.PatchComment(patchModel)
+		}
+		if err == nil {
+			err = s.Comment().PatchComment(ctx, patchModel)
+		}
+		if err != nil {
+			return fmt.Errorf("error patching comment: %w", err)
+		}
+	case "comment.delete":
+		var payload DeletePayload
+		err := envelope.decodePayload(&payload)
+		if err == nil {
+			err = s.Comment().DeleteComment(ctx, payload.ID)
+		}
+		if ignoreNotFound(err) != nil {
+			return fmt.Errorf("error deleting comment: %w", err)
+		}
+	case "comment.delete_by_memory":
+		var payload DeleteByMemoryPayload
+		if err := envelope.decodePayload(&payload); err != nil {
+			return fmt.Errorf("error deleting comments: %w", err)
+		}
+		deleted, err := deleteComments(ctx, s, &memory.GetAllCommentsRequest{MemoryId: payload.MemoryID})
+		if err != nil {
+			return fmt.Errorf("error deleting comments of memory %s after %d: %w", payload.MemoryID, deleted, err)
+		}
+		log.Printf("deleted %d comments of memory %s", deleted, payload.MemoryID)
+	case "comment.delete_by_user":
+		var payload DeleteByUserPayload
+		if err := envelope.decodePayload(&payload); err != nil {
+			return fmt.Errorf("error deleting comments: %w", err)
+		}
+		deleted, err := deleteComments(ctx, s, &memory.GetAllCommentsRequest{UserId: payload.UserID})
+		if err != nil {
+			return fmt.Errorf("error deleting comments of user %s after %d: %w", payload.UserID, deleted, err)
 		}
+		log.Printf("deleted %d comments of user %s", deleted, payload.UserID)
+	default:
+		return unknownOperation(envelope)
 	}
+	return nil
 }
@@ -0,0 +1,271 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// Options configure how a consumer processes messages and deals with failed
+// ones.
+type Options struct {
+	// Concurrency is how many messages are processed at once. Messages with
+	// the same key are processed one after another in the order they were
+	// consumed. Zero or one processes all messages in order.
+	Concurrency int
+
+	// BatchSize is how many queued messages a worker takes at once.
+	// Consecutive creates in a batch are written with one bulk insert in one
+	// transaction; if that fails they are processed one at a time. Zero or
+	// one processes every message on its own.
+	BatchSize int
+
+	// Deduplicate records the id of every applied command in the transaction
+	// of its writes and skips commands that were applied before. The
+	// storage must support transactions; MongoDB needs a replica set.
+	Deduplicate bool
+
+	// DeadLetter receives the messages that cannot be processed. With a nil
+	// DeadLetter failures are only logged.
+	DeadLetter *DeadLetterQueue
+
+	// Retry controls how retryable failures are retried before a message is
+	// given up on.
+	Retry RetryPolicy
+}
+
+// RetryPolicy controls how often a message is processed again after a
+// retryable failure. The zero value does not retry.
+type RetryPolicy struct {
+	MaxAttempts    int           // Attempts per message, including the first
+	InitialBackoff time.Duration // Wait before the first retry; doubled for every further retry
+	MaxBackoff     time.Duration // Upper bound of the wait
+}
+
+// backoff returns how long to wait before the given retry (1 for the first),
+// randomized to between half and all of the exponential backoff so that
+// consumers do not retry in lockstep.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryable reports whether a message that failed with err may succeed when
+// processed again: the database was unreachable, the write lost a race with
+// a concurrent transaction or it timed out. Version conflicts, invalid or
+// missing data and unclassified errors are permanent.
+func retryable(err error) bool {
+	switch {
+	case errors.Is(err, storage.ErrVersionConflict):
+		return false
+	case errors.Is(err, storage.ErrUnavailable),
+		errors.Is(err, storage.ErrConflict),
+		errors.Is(err, context.DeadlineExceeded):
+		return true
+	}
+	return false
+}
+
+// handlerFunc processes a single message.
+type handlerFunc func(ctx context.Context, msg kafka.Message) error
+
+// bulkHandlerFunc processes commands with the same operation at once. It
+// either applies all of them or none.
+type bulkHandlerFunc func(ctx context.Context, envelopes []*Envelope) error
+
+// queueSize is how many messages may wait for each worker of a consumer.
+const queueSize = 16
+
+// consume fetches messages from reader and passes them to handle until ctx
+// is done, fetching fails or a failure cannot be dead-lettered. Up to
+// opts.Concurrency messages are processed at once; messages with the same
+// ordering key go to the same worker, so they are processed in order. Workers
+// take up to opts.BatchSize messages at a time and pass runs of consecutive
+// commands whose operation has a handler in bulk to that handler.
+// Retryable failures are retried as opts.Retry allows; messages that still
+// fail are sent to opts.DeadLetter. Offsets are committed once every message
+// before them in their partition is processed.
+func consume(ctx context.Context, reader *kafka.Reader, opts Options, handle handlerFunc, bulk map[string]bulkHandlerFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		failOnce sync.Once
+		failErr  error
+	)
+	fail := func(err error) {
+		failOnce.Do(func() {
+			failErr = err
+			cancel()
+		})
+	}
+
+	// Processed messages are committed by a single goroutine, so the
+	// committed offset of a partition only moves forward. Messages processed
+	// meanwhile are committed together.
+	batchSize := max(opts.BatchSize, 1)
+	offsets := newOffsetTracker()
+	processed := make(chan kafka.Message, max(queueSize, batchSize))
+	committed := make(chan struct{})
+	go func() {
+		defer close(committed)
+		for msg := range processed {
+			commits := make(map[int]kafka.Message)
+			for more := true; more; {
+				if commit, ok := offsets.complete(msg); ok {
+					commits[commit.Partition] = commit
+				}
+				select {
+				case msg, more = <-processed:
+				default:
+					more = false
+				}
+			}
+			if len(commits) == 0 {
+				continue
+			}
+			msgs := make([]kafka.Message, 0, len(commits))
+			for _, commit := range commits {
+				msgs = append(msgs, commit)
+			}
+			if err := reader.CommitMessages(ctx, msgs...); err != nil {
+				fail(fmt.Errorf("error committing messages: %w", err))
+			}
+		}
+	}()
+
+	// settle hands msg to the committer once it is processed, dead-lettering
+	// it first if processing failed.
+	settle := func(msg kafka.Message, attempts int, err error) {
+		if ctx.Err() != nil {
+			// Shutting down; the message is fetched again on restart.
+			return
+		}
+		if err != nil {
+			if err := deadLetter(ctx, opts.DeadLetter, msg, err, attempts); err != nil {
+				fail(err)
+				return
+			}
+		}
+		processed <- msg
+	}
+
+	var workers sync.WaitGroup
+	queues := make([]chan kafka.Message, max(opts.Concurrency, 1))
+	for i := range queues {
+		queues[i] = make(chan kafka.Message, max(queueSize, batchSize))
+		workers.Add(1)
+		go func(queue <-chan kafka.Message) {
+			defer workers.Done()
+			batch := make([]kafka.Message, 0, batchSize)
+			for msg := range queue {
+				batch = append(batch[:0], msg)
+			fill:
+				for len(batch) < batchSize {
+					select {
+					case msg, ok := <-queue:
+						if !ok {
+							break fill
+						}
+						batch = append(batch, msg)
+					default:
+						break fill
+					}
+				}
+
+				processBatch(ctx, batch, opts.Retry, handle, bulk, settle)
+			}
+		}(queues[i])
+	}
+
+	for ctx.Err() == nil {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			fail(fmt.Errorf("error fetching message: %w", err))
+			break
+		}
+		offsets.add(msg)
+		select {
+		case queues[worker(msg, len(queues))] <- msg:
+		case <-ctx.Done():
+		}
+	}
+	fail(ctx.Err())
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	workers.Wait()
+	close(processed)
+	<-committed
+	return failErr
+}
+
+// orderingKey returns the key that orders msg relative to other messages:
+// its key, which producers set to the id of the entity the command is about.
+// Unkeyed messages and legacy messages keyed by operation are ordered by
+// partition.
+func orderingKey(msg kafka.Message) string {
+	if _, ok := payloadSchemas[string(msg.Key)]; ok || len(msg.Key) == 0 {
+		return "partition:" + strconv.Itoa(msg.Partition)
+	}
+	return string(msg.Key)
+}
+
+// worker returns which of n workers processes msg.
+func worker(msg kafka.Message, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(orderingKey(msg)))
+	return int(h.Sum32() % uint32(n))
+}
+
+// process passes msg to handle, retrying retryable failures with backoff as
+// policy allows. It returns the number of attempts made and the error of the
+// last one.
+func process(ctx context.Context, msg kafka.Message, policy RetryPolicy, handle handlerFunc) (int, error) {
+	for attempts := 1; ; attempts++ {
+		err := handle(ctx, msg)
+		if err == nil || !retryable(err) || attempts >= policy.MaxAttempts || ctx.Err() != nil {
+			return attempts, err
+		}
+
+		wait := policy.backoff(attempts)
+		log.Printf("retrying message %s/%d@%d in %s: %v", msg.Topic, msg.Partition, msg.Offset, wait, err)
+		select {
+		case <-ctx.Done():
+			return attempts, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// deadLetter sends msg, which failed with cause after the given number of
+// attempts, to dlq. Without a dead-letter queue the failure is only logged.
+func deadLetter(ctx context.Context, dlq *DeadLetterQueue, msg kafka.Message, cause error, attempts int) error {
+	log.Printf("error processing message %s/%d@%d: %v", msg.Topic, msg.Partition, msg.Offset, cause)
+	if dlq == nil {
+		return nil
+	}
+	if err := dlq.Send(ctx, msg, cause, previousAttempts(msg)+attempts); err != nil {
+		return fmt.Errorf("error dead-lettering message: %w", err)
+	}
+	return nil
+}
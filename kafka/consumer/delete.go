@@ -0,0 +1,79 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// The bulk deletes below list the first page of matching entities and move
+// them to the trash until none are left. Trashed entities drop out of the
+// listing, so a replayed command picks up where a failed one stopped.
+
+// deleteMemoriesOfUser moves all memories of userID, with their media and
+// comments, to the trash and returns how many memories it deleted.
+func deleteMemoriesOfUser(ctx context.Context, s storage.StorageI, userID string) (int, error) {
+	deleted := 0
+	for {
+		page, err := s.Memory().GetAllMemories(ctx, &memory.GetAllMemoriesRequest{UserId: userID, Limit: helper.MaxLimit})
+		if err != nil || len(page.Memories) == 0 {
+			return deleted, err
+		}
+		for _, m := range page.Memories {
+			if _, err := s.Memory().DeleteMemory(ctx, m.Id); ignoreNotFound(err) != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+}
+
+// deleteMediaOfMemory moves all media of memoryID to the trash and returns
+// how many it deleted.
+func deleteMediaOfMemory(ctx context.Context, s storage.StorageI, memoryID string) (int, error) {
+	deleted := 0
+	for {
+		page, err := s.Media().GetAllMedia(ctx, &memory.GetAllMediaRequest{MemoryId: memoryID, Limit: helper.MaxLimit})
+		if err != nil || len(page.Media) == 0 {
+			return deleted, err
+		}
+		for _, m := range page.Media {
+			if err := s.Media().DeleteMedia(ctx, m.Id); ignoreNotFound(err) != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+}
+
+// deleteComments moves all comments matching req's memory and user filters
+// to the trash and returns how many it deleted.
+func deleteComments(ctx context.Context, s storage.StorageI, req *memory.GetAllCommentsRequest) (int, error) {
+	req.Limit = helper.MaxLimit
+	deleted := 0
+	for {
+		page, err := s.Comment().GetAllComments(ctx, req)
+		if err != nil || len(page.Comments) == 0 {
+			return deleted, err
+		}
+		for _, c := range page.Comments {
+			if err := s.Comment().DeleteComment(ctx, c.Id); ignoreNotFound(err) != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+}
+
+// ignoreNotFound returns nil for not found errors. Deleting something that is
+// already gone is not a failure for a delete command, so redelivered
+// commands succeed.
+func ignoreNotFound(err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	return err
+}
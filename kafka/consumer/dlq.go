@@ -0,0 +1,245 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers added to dead-lettered messages. The original headers, key and
+// value are kept.
+const (
+	HeaderError     = "dlq-error"              // Why processing failed
+	HeaderTopic     = "dlq-original-topic"     // Topic the message was consumed from
+	HeaderPartition = "dlq-original-partition" // Partition the message was consumed from
+	HeaderOffset    = "dlq-original-offset"    // Offset of the message in that partition
+	HeaderAttempts  = "dlq-attempts"           // Number of times processing was attempted
+	HeaderFailedAt  = "dlq-failed-at"          // When the message was dead-lettered (RFC3339)
+)
+
+const (
+	// redriveIdleTimeout is how long Redrive waits for another message before
+	// deciding the dead-letter topic is drained.
+	redriveIdleTimeout = 10 * time.Second
+
+	// listReadTimeout bounds List when ctx has no deadline.
+	listReadTimeout = 30 * time.Second
+
+	// maxBatchBytes is the largest fetch List makes at once.
+	maxBatchBytes = 10e6
+)
+
+// DeadLetterQueue keeps messages the consumers could not process on a
+// dead-letter topic, so they can be inspected and re-driven into the topic
+// they came from.
+type DeadLetterQueue struct {
+	brokers []string
+	topic   string
+	writer  *kafka.Writer
+}
+
+// NewDeadLetterQueue creates a DeadLetterQueue on topic.
+func NewDeadLetterQueue(kafkaBrokers []string, topic string) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		brokers: kafkaBrokers,
+		topic:   topic,
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(kafkaBrokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+// Send writes msg to the dead-letter topic with headers recording cause,
+// where msg was consumed from and how many attempts were made.
+func (q *DeadLetterQueue) Send(ctx context.Context, msg kafka.Message, cause error, attempts int) error {
+	headers := append(withoutDeadLetterHeaders(msg.Headers),
+		kafka.Header{Key: HeaderError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderTopic, Value: []byte(msg.Topic)},
+		kafka.Header{Key: HeaderPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: HeaderOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kafka.Header{Key: HeaderAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+	return q.writer.WriteMessages(ctx, kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+}
+
+// List returns up to limit messages from the dead-letter topic, partition by
+// partition, without consuming them. A limit of 0 or less lists all of them.
+func (q *DeadLetterQueue) List(ctx context.Context, limit int) ([]kafka.Message, error) {
+	conn, err := kafka.DialContext(ctx, "tcp", q.brokers[0])
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := conn.ReadPartitions(q.topic)
+	conn.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []kafka.Message
+	for _, partition := range partitions {
+		if limit > 0 && len(msgs) >= limit {
+			break
+		}
+		msgs, err = q.readPartition(ctx, partition.ID, msgs, limit)
+		if err != nil {
+			return msgs, err
+		}
+	}
+	return msgs, nil
+}
+
+// readPartition appends the messages of partition to msgs, stopping at the
+// end of the partition or once msgs holds limit messages.
+func (q *DeadLetterQueue) readPartition(ctx context.Context, partition int, msgs []kafka.Message, limit int) ([]kafka.Message, error) {
+	conn, err := kafka.DialLeader(ctx, "tcp", q.brokers[0], q.topic, partition)
+	if err != nil {
+		return msgs, err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(listReadTimeout)
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return msgs, err
+	}
+
+	offset, last, err := conn.ReadOffsets()
+	if err != nil {
+		return msgs, err
+	}
+	if _, err := conn.Seek(offset, kafka.SeekAbsolute); err != nil {
+		return msgs, err
+	}
+
+	for offset < last && (limit <= 0 || len(msgs) < limit) {
+		batch := conn.ReadBatch(1, maxBatchBytes)
+		for offset < last && (limit <= 0 || len(msgs) < limit) {
+			msg, err := batch.ReadMessage()
+			if err != nil {
+				break
+			}
+			msgs = append(msgs, msg)
+			offset = msg.Offset + 1
+		}
+		if err := batch.Close(); err != nil {
+			return msgs, err
+		}
+	}
+	return msgs, nil
+}
+
+// Redrive writes up to limit dead-lettered messages back into the topics they
+// were consumed from and returns how many it re-drove. Progress is tracked
+// by a consumer group, so each message is re-driven once; Redrive stops once
+// no new message arrives for a while. A limit of 0 or less re-drives all of
+// them.
+func (q *DeadLetterQueue) Redrive(ctx context.Context, limit int) (int, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: q.brokers,
+		Topic:   q.topic,
+		GroupID: q.topic + "-redrive",
+	})
+	defer reader.Close()
+
+	writer := &kafka.Writer{
+		Addr:     kafka.TCP(q.brokers...),
+		Balancer: &kafka.Hash{},
+	}
+	defer writer.Close()
+
+	redriven := 0
+	for limit <= 0 || redriven < limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, redriveIdleTimeout)
+		msg, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				break
+			}
+			return redriven, fmt.Errorf("error fetching dead letter: %w", err)
+		}
+
+		original, err := originalMessage(msg)
+		if err != nil {
+			return redriven, err
+		}
+		if err := writer.WriteMessages(ctx, original); err != nil {
+			return redriven, fmt.Errorf("error re-driving message to %s: %w", original.Topic, err)
+		}
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			return redriven, fmt.Errorf("error committing dead letter: %w", err)
+		}
+		redriven++
+	}
+	return redriven, nil
+}
+
+// Close closes the dead-letter writer.
+func (q *DeadLetterQueue) Close() error {
+	return q.writer.Close()
+}
+
+// originalMessage rebuilds the message a dead letter was made from. The
+// attempt count is kept so that a message failing again is dead-lettered
+// with the total number of attempts.
+func originalMessage(msg kafka.Message) (kafka.Message, error) {
+	topic := Header(msg, HeaderTopic)
+	if topic == "" {
+		return kafka.Message{}, fmt.Errorf("dead letter %d@%d has no %s header", msg.Partition, msg.Offset, HeaderTopic)
+	}
+	headers := withoutDeadLetterHeaders(msg.Headers)
+	if attempts := Header(msg, HeaderAttempts); attempts != "" {
+		headers = append(headers, kafka.Header{Key: HeaderAttempts, Value: []byte(attempts)})
+	}
+	return kafka.Message{
+		Topic:   topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}, nil
+}
+
+// previousAttempts returns the number of attempts recorded on a re-driven
+// message, or 0 for a new one.
+func previousAttempts(msg kafka.Message) int {
+	attempts, _ := strconv.Atoi(Header(msg, HeaderAttempts))
+	return attempts
+}
+
+// Header returns the value of the last header of msg named key, or "" if
+// there is none.
+func Header(msg kafka.Message, key string) string {
+	for i := len(msg.Headers) - 1; i >= 0; i-- {
+		if msg.Headers[i].Key == key {
+			return string(msg.Headers[i].Value)
+		}
+	}
+	return ""
+}
+
+// withoutDeadLetterHeaders returns a copy of headers without the ones added
+// by Send.
+func withoutDeadLetterHeaders(headers []kafka.Header) []kafka.Header {
+	kept := make([]kafka.Header, 0, len(headers))
+	for _, h := range headers {
+		if !strings.HasPrefix(h.Key, "dlq-") {
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
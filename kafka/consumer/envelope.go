@@ -0,0 +1,175 @@
+package consumer
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/segmentio/kafka-go"
+	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
+)
+
+// HeaderOperation names the operation of a legacy message, so that producers
+// can key legacy messages by entity as well. Without it the key names the
+// operation.
+const HeaderOperation = "operation"
+
+// SchemaVersion is the version of the envelope and payload schemas the
+// consumers understand.
+const SchemaVersion = 1
+
+// Envelope is a command on a Kafka topic. Producers key commands by the id of
+// the entity they are about, so that the commands of an entity are processed
+// in order. Messages that carry no schema_version are legacy commands: their
+// operation header or key names the operation and their whole value is the
+// payload.
+type Envelope struct {
+	CommandID     string          `json:"command_id"`      // Unique id of the command
+	Operation     string          `json:"operation"`       // e.g. memory.create
+	SchemaVersion int             `json:"schema_version"`  // Version of the envelope and payload schemas
+	Actor         string          `json:"actor,omitempty"` // UUID of the user who issued the command
+	Timestamp     time.Time       `json:"timestamp"`       // When the command was issued
+	Payload       json.RawMessage `json:"payload"`         // Operation specific, see schemas/
+}
+
+// operations lists the commands the consumers understand. The payload schema
+// of entity.action is schemas/v1/entity.json#/$defs/action.
+var operations = []string{
+	"memory.create", "memory.update", "memory.patch", "memory.delete", "memory.delete_by_user",
+	"media.create", "media.update", "media.patch", "media.delete", "media.delete_by_memory",
+	"comment.create", "comment.update", "comment.patch", "comment.delete", "comment.delete_by_memory", "comment.delete_by_user",
+}
+
+//go:embed schemas
+var schemaFS embed.FS
+
+var (
+	envelopeSchema *jsonschema.Schema
+	payloadSchemas = make(map[string]*jsonschema.Schema, len(operations))
+)
+
+func init() {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+	files, err := schemaFS.ReadDir("schemas/v1")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		data, err := schemaFS.ReadFile("schemas/v1/" + file.Name())
+		if err != nil {
+			panic(err)
+		}
+		if err := compiler.AddResource(file.Name(), bytes.NewReader(data)); err != nil {
+			panic(err)
+		}
+	}
+
+	envelopeSchema = compiler.MustCompile("envelope.json")
+	for _, operation := range operations {
+		entity, action, _ := strings.Cut(operation, ".")
+		payloadSchemas[operation] = compiler.MustCompile(entity + ".json#/$defs/" + action)
+	}
+}
+
+// decodeEnvelope reads the command in msg, validating the envelope against
+// its schema. Legacy messages are wrapped in an envelope.
+func decodeEnvelope(msg kafka.Message) (*Envelope, error) {
+	var version struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(msg.Value, &version); err != nil {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "error unmarshalling message: %w", err)
+	}
+	if version.SchemaVersion == nil {
+		operation := Header(msg, HeaderOperation)
+		if operation == "" {
+			operation = string(msg.Key)
+		}
+		return &Envelope{CommandID: legacyCommandID(msg), Operation: operation, Payload: msg.Value}, nil
+	}
+	if *version.SchemaVersion != SchemaVersion {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "unsupported schema version %d", *version.SchemaVersion)
+	}
+
+	if err := validateJSON(envelopeSchema, msg.Value, "envelope"); err != nil {
+		return nil, err
+	}
+	var envelope Envelope
+	if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "error unmarshalling message: %w", err)
+	}
+	return &envelope, nil
+}
+
+// legacyCommandID identifies a legacy message, which carries no command id,
+// by where it was consumed from. A message delivered again after a crash or
+// rebalance has the same id; a re-driven dead letter is a new message.
+func legacyCommandID(msg kafka.Message) string {
+	return fmt.Sprintf("%s/%d@%d", msg.Topic, msg.Partition, msg.Offset)
+}
+
+// decodePayload validates the payload of e against the schema of its
+// operation and unmarshals it into v.
+func (e *Envelope) decodePayload(v interface{}) error {
+	schema, ok := payloadSchemas[e.Operation]
+	if !ok {
+		return unknownOperation(e)
+	}
+	if err := validateJSON(schema, e.Payload, "payload"); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return storage.Errorf(storage.ErrInvalidArgument, "error unmarshalling payload: %w", err)
+	}
+	return nil
+}
+
+// validateJSON validates data against schema. Schema violations are returned
+// as a *validation.Error naming the offending fields; violations of data as
+// a whole are reported on root.
+func validateJSON(schema *jsonschema.Schema, data []byte, root string) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return storage.Errorf(storage.ErrInvalidArgument, "error unmarshalling message: %w", err)
+	}
+
+	err := schema.Validate(v)
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	var violations []validation.FieldViolation
+	schemaViolations(verr, root, &violations)
+	return &validation.Error{Violations: violations}
+}
+
+// schemaViolations appends the innermost causes of verr to violations.
+func schemaViolations(verr *jsonschema.ValidationError, root string, violations *[]validation.FieldViolation) {
+	if len(verr.Causes) == 0 {
+		field := strings.ReplaceAll(strings.TrimPrefix(verr.InstanceLocation, "/"), "/", ".")
+		if field == "" {
+			field = root
+		}
+		*violations = append(*violations, validation.FieldViolation{Field: field, Description: verr.Message})
+		return
+	}
+	for _, cause := range verr.Causes {
+		schemaViolations(cause, root, violations)
+	}
+}
+
+// unknownOperation reports a command that names no operation of the topic it
+// was consumed from.
+func unknownOperation(e *Envelope) error {
+	return storage.Errorf(storage.ErrInvalidArgument, "unknown operation: %q", e.Operation)
+}
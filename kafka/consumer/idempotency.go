@@ -0,0 +1,77 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// errProcessed reports a command that was applied before.
+var errProcessed = errors.New("command already processed")
+
+// applyFunc writes the changes of a command to s.
+type applyFunc func(ctx context.Context, s storage.StorageI, envelope *Envelope) error
+
+// applyOnce applies the command in envelope. With opts.Deduplicate the
+// command id is recorded in the transaction of its writes, so that a command
+// delivered again after a crash or rebalance is skipped instead of being
+// applied twice.
+func applyOnce(ctx context.Context, s storage.StorageI, opts Options, envelope *Envelope, apply applyFunc) error {
+	if !opts.Deduplicate || envelope.CommandID == "" {
+		return apply(ctx, s, envelope)
+	}
+
+	err := s.WithTx(ctx, func(tx storage.StorageI) error {
+		err := tx.Command().MarkCommandProcessed(ctx, envelope.CommandID, envelope.Operation)
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			return errProcessed
+		}
+		if err != nil {
+			return err
+		}
+		return apply(ctx, tx, envelope)
+	})
+	if errors.Is(err, errProcessed) {
+		log.Printf("skipping %s command %s: already processed", envelope.Operation, envelope.CommandID)
+		return nil
+	}
+	return err
+}
+
+// bulkApplyFunc writes the changes of commands with the same operation to s
+// at once.
+type bulkApplyFunc func(ctx context.Context, s storage.StorageI, envelopes []*Envelope) error
+
+// applyAllOnce applies the commands in envelopes together. With
+// opts.Deduplicate their ids are recorded in the transaction of the writes
+// and commands that were applied before are left out.
+func applyAllOnce(ctx context.Context, s storage.StorageI, opts Options, envelopes []*Envelope, apply bulkApplyFunc) error {
+	if !opts.Deduplicate {
+		return apply(ctx, s, envelopes)
+	}
+
+	return s.WithTx(ctx, func(tx storage.StorageI) error {
+		pending := make([]*Envelope, 0, len(envelopes))
+		for _, envelope := range envelopes {
+			if envelope.CommandID == "" {
+				pending = append(pending, envelope)
+				continue
+			}
+			err := tx.Command().MarkCommandProcessed(ctx, envelope.CommandID, envelope.Operation)
+			if errors.Is(err, storage.ErrAlreadyExists) {
+				log.Printf("skipping %s command %s: already processed", envelope.Operation, envelope.CommandID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			pending = append(pending, envelope)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		return apply(ctx, tx, pending)
+	})
+}
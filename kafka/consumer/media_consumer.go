@@ -2,80 +2,138 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/time_capsule/memory-service/models"
 	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
 )
 
 // MediaConsumer consumes Kafka messages related to media.
 type MediaConsumer struct {
 	reader  *kafka.Reader
 	storage storage.StorageI
+	opts    Options
 }
 
 // NewMediaConsumer creates a new MediaConsumer instance.
-func NewMediaConsumer(kafkaBrokers []string, topic string, storage storage.StorageI) *MediaConsumer {
+func NewMediaConsumer(kafkaBrokers []string, topic string, storage storage.StorageI, opts Options) *MediaConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers: kafkaBrokers,
 		Topic:   topic,
 		GroupID: "media-group", // Choose a suitable group ID
 	})
-	return &MediaConsumer{reader: reader, storage: storage}
+	return &MediaConsumer{reader: reader, storage: storage, opts: opts}
 }
 
 // Consume starts consuming messages from the Kafka topic.
 func (c *MediaConsumer) Consume(ctx context.Context) error {
-	for {
-		msg, err := c.reader.FetchMessage(ctx)
+	return consume(ctx, c.reader, c.opts, c.handle, map[string]bulkHandlerFunc{
+		"media.create": c.handleCreates,
+	})
+}
+
+// handle processes a single media command.
+func (c *MediaConsumer) handle(ctx context.Context, msg kafka.Message) error {
+	envelope, err := decodeEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	return applyOnce(ctx, c.storage, c.opts, envelope, c.apply)
+}
+
+// handleCreates creates the media of several media.create commands at once.
+func (c *MediaConsumer) handleCreates(ctx context.Context, envelopes []*Envelope) error {
+	return applyAllOnce(ctx, c.storage, c.opts, envelopes, c.applyCreates)
+}
+
+// applyCreates writes the media of media.create commands to s with one
+// bulk insert.
+func (c *MediaConsumer) applyCreates(ctx context.Context, s storage.StorageI, envelopes []*Envelope) error {
+	createModels := make([]*models.CreateMediaModel, len(envelopes))
+	for i, envelope := range envelopes {
+		var createModel models.CreateMediaModel
+		err := envelope.decodePayload(&createModel)
+		if err == nil {
+			err = validation.CreateMedia(&createModel)
+		}
 		if err != nil {
-			return fmt.Errorf("error fetching message: %w", err)
+			return fmt.Errorf("error creating media: %w", err)
 		}
+		createModels[i] = &createModel
+	}
+	if _, err := s.Media().BulkCreateMedia(ctx, createModels); err != nil {
+		return fmt.Errorf("error creating media: %w", err)
+	}
+	return nil
+}
 
-		var mediaModel models.CreateMediaModel // Or the appropriate model for the message
-		if err := json.Unmarshal(msg.Value, &mediaModel); err != nil {
-			log.Printf("error unmarshalling message: %v", err)
-			continue // Skip to the next message
+// apply writes the changes of a media command to s.
+func (c *MediaConsumer) apply(ctx context.Context, s storage.StorageI, envelope *Envelope) error {
+	switch envelope.Operation {
+	case "media.create":
+		var createModel models.CreateMediaModel
+		err := envelope.decodePayload(&createModel)
+		if err == nil {
+			err = validation.CreateMedia(&createModel)
 		}
-
-		// Process the mediaModel based on the message type (create, update, patch)
-		switch string(msg.Key) {
-		case "media.create":
-			if _, err := c.storage.Media().CreateMedia(ctx, &mediaModel); err != nil {
-				log.Printf("error creating media: %v", err)
-			}
-		case "media.update":
-			updateModel := &models.UpdateMediaModel{
-				ID:       mediaModel.ID,
-				MemoryID: mediaModel.MemoryID,
-				Type:     mediaModel.Type,
-				URL:      mediaModel.URL,
-				Created:  mediaModel.Created,
-			}
-			if err := c.storage.Media().UpdateMedia(ctx, updateModel); err != nil {
-				log.Printf("error updating media: %v", err)
-			}
-		case "media.patch":
-			patchModel := &models.PatchMediaModel{
-				ID:       mediaModel.ID,
-				MemoryID: &mediaModel.MemoryID,
-				Type:     &mediaModel.Type,
-				URL:      &mediaModel.URL,
-				Created:  &mediaModel.Created,
-			}
-			if err := c.storage.Media().PatchMedia(ctx, patchModel); err != nil {
-				log.Printf("error patching media: %v", err)
-			}
-		default:
-			log.Printf("unknown message key: %s", msg.Key)
+		if err == nil {
+			_, err = s.Media().CreateMedia(ctx, &createModel)
 		}
-
-		// Commit the message
-		if err := c.reader.CommitMessages(ctx, msg); err != nil {
-			return fmt.Errorf("error committing message: %w", err)
+		if err != nil {
+			return fmt.Errorf("error creating media: %w", err)
+		}
+	case "media.update":
+		var updateModel models.UpdateMediaModel
+		err := envelope.decodePayload(&updateModel)
+		if err == nil {
+			err = validation.UpdateMedia(&updateModel)
+		}
+		if err == nil {
+			err = s.Media().UpdateMedia(ctx, &updateModel)
+		}
+		if err != nil {
+			return fmt.Errorf("error updating media: %w", err)
+		}
+	case "media.patch":
+		var patch MediaPatch
+		err := envelope.decodePayload(&patch)
+		var patchModel *models.PatchMediaModel
+		if err == nil {
+			patchModel, err = patch.model()
+		}
+		if err == nil {
+			err = validation.PatchMedia(patchModel)
+		}
+		if err == nil {
+			err = s.Media().PatchMedia(ctx, patchModel)
+		}
+		if err != nil {
+			return fmt.Errorf("error patching media: %w", err)
+		}
+	case "media.delete":
+		var payload DeletePayload
+		err := envelope.decodePayload(&payload)
+		if err == nil {
+			err = s.Media().DeleteMedia(ctx, payload.ID)
+		}
+		if ignoreNotFound(err) != nil {
+			return fmt.Errorf("error deleting media: %w", err)
+		}
+	case "media.delete_by_memory":
+		var payload DeleteByMemoryPayload
+		if err := envelope.decodePayload(&payload); err != nil {
+			return fmt.Errorf("error deleting media: %w", err)
+		}
+		deleted, err := deleteMediaOfMemory(ctx, s, payload.MemoryID)
+		if err != nil {
+			return fmt.Errorf("error deleting media of memory %s after %d: %w", payload.MemoryID, deleted, err)
 		}
+		log.Printf("deleted %d media of memory %s", deleted, payload.MemoryID)
+	default:
+		return unknownOperation(envelope)
 	}
+	return nil
 }
@@ -2,89 +2,144 @@ package consumer
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/time_capsule/memory-service/models"
 	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
 )
 
 // MemoryConsumer consumes Kafka messages related to memories.
 type MemoryConsumer struct {
 	reader  *kafka.Reader
 	storage storage.StorageI
+	opts    Options
 }
 
 // NewMemoryConsumer creates a new MemoryConsumer instance.
-func NewMemoryConsumer(kafkaBrokers []string, topic string, storage storage.StorageI) *MemoryConsumer {
+func NewMemoryConsumer(kafkaBrokers []string, topic string, storage storage.StorageI, opts Options) *MemoryConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers: kafkaBrokers,
 		Topic:   topic,
 		GroupID: "memory-group",
 	})
-	return &MemoryConsumer{reader: reader, storage: storage}
+	return &MemoryConsumer{reader: reader, storage: storage, opts: opts}
 }
 
 // Consume starts consuming messages from the Kafka topic.
 func (c *MemoryConsumer) Consume(ctx context.Context) error {
-	for {
-		msg, err := c.reader.FetchMessage(ctx)
-		if err != nil {
-			return fmt.Errorf("error fetching message: %w", err)
+	return consume(ctx, c.reader, c.opts, c.handle, map[string]bulkHandlerFunc{
+		"memory.create": c.handleCreates,
+	})
+}
+
+// handle processes a single memory command.
+func (c *MemoryConsumer) handle(ctx context.Context, msg kafka.Message) error {
+	envelope, err := decodeEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	return applyOnce(ctx, c.storage, c.opts, envelope, c.apply)
+}
+
+// handleCreates creates the memories of several memory.create commands at once.
+func (c *MemoryConsumer) handleCreates(ctx context.Context, envelopes []*Envelope) error {
+	return applyAllOnce(ctx, c.storage, c.opts, envelopes, c.applyCreates)
+}
+
+// applyCreates writes the memories of memory.create commands to s with one
+// bulk insert.
+func (c *MemoryConsumer) applyCreates(ctx context.Context, s storage.StorageI, envelopes []*Envelope) error {
+	createModels := make([]*models.CreateMemoryModel, len(envelopes))
+	for i, envelope := range envelopes {
+		var createModel models.CreateMemoryModel
+		err := envelope.decodePayload(&createModel)
+		if err == nil {
+			err = validation.CreateMemory(&createModel)
 		}
-		var memoryModel models.CreateMemoryModel // Or the appropriate model for the message
-		if err := json.Unmarshal(msg.Value, &memoryModel); err != nil {
-			log.Printf("error unmarshalling message: %v", err)
-			continue // Skip to the next message
+		if err != nil {
+			return fmt.Errorf("error creating memories: %w", err)
 		}
+		createModels[i] = &createModel
+	}
+	if _, err := s.Memory().BulkCreateMemories(ctx, createModels); err != nil {
+		return fmt.Errorf("error creating memories: %w", err)
+	}
+	return nil
+}
 
-		// Process the memoryModel based on the message type (create, update, patch)
-		switch string(msg.Key) {
-		case "memory.create":
-			if _, err := c.storage.Memory().CreateMemory(ctx, &memoryModel); err != nil {
-				log.Printf("error creating memory: %v", err)
-			}
-
-		case "memory.update":
-			updateModel := &models.UpdateMemoryModel{
-				ID:          memoryModel.ID, // Assuming ID is part of the message
-				UserID:      memoryModel.UserID,
-				Title:       memoryModel.Title,
-				Description: memoryModel.Description,
-				Date:        memoryModel.Date,
-				Tags:        memoryModel.Tags,
-				Latitude:    memoryModel.Latitude,
-				Longitude:   memoryModel.Longitude,
-				PlaceName:   memoryModel.PlaceName,
-				Privacy:     memoryModel.Privacy,
-			}
-			if err := c.storage.Memory().UpdateMemory(ctx, updateModel); err != nil {
-				log.Printf("error updating memory: %v", err)
-			}
-		case "memory.patch":
-			patchModel := &models.PatchMemoryModel{
-				ID:          memoryModel.ID, // Assuming ID is part of the message
-				Title:       &memoryModel.Title,
-				Description: &memoryModel.Description,
-				Date:        &memoryModel.Date,
-				Tags:        &memoryModel.Tags,
-				Latitude:    &memoryModel.Latitude,
-				Longitude:   &memoryModel.Longitude,
-				PlaceName:   &memoryModel.PlaceName,
-				Privacy:     &memoryModel.Privacy,
+// apply writes the changes of a memory command to s.
+func (c *MemoryConsumer) apply(ctx context.Context, s storage.StorageI, envelope *Envelope) error {
+	switch envelope.Operation {
+	case "memory.create":
+		var createModel models.CreateMemoryModel
+		err := envelope.decodePayload(&createModel)
+		if err == nil {
+			err = validation.CreateMemory(&createModel)
+		}
+		if err == nil {
+			_, err = s.Memory().CreateMemory(ctx, &createModel)
+		}
+		if err != nil {
+			return fmt.Errorf("error creating memory: %w", err)
+		}
+	case "memory.update":
+		var updateModel models.UpdateMemoryModel
+		err := envelope.decodePayload(&updateModel)
+		if err == nil {
+			if updateModel.ChangedBy == "" {
+				updateModel.ChangedBy = envelope.Actor
 			}
-			if err := c.storage.Memory().PatchMemory(ctx, patchModel); err != nil {
-				log.Printf("error patching memory: %v", err)
+			err = validation.UpdateMemory(&updateModel)
+		}
+		if err == nil {
+			err = s.Memory().UpdateMemory(ctx, &updateModel)
+		}
+		if err != nil {
+			return fmt.Errorf("error updating memory: %w", err)
+		}
+	case "memory.patch":
+		var patch MemoryPatch
+		err := envelope.decodePayload(&patch)
+		var patchModel *models.PatchMemoryModel
+		if err == nil {
+			patchModel, err = patch.model()
+		}
+		if err == nil {
+			if patchModel.ChangedBy == "" {
+				patchModel.ChangedBy = envelope.Actor
 			}
-		default:
-			log.Printf("unknown message key: %s", msg.Key)
+			err = validation.PatchMemory(patchModel)
 		}
-
-		// Commit the message
-		if err := c.reader.CommitMessages(ctx, msg); err != nil {
-			return fmt.Errorf("error committing message: %w", err)
+		if err == nil {
+			err = s.Memory().PatchMemory(ctx, patchModel)
+		}
+		if err != nil {
+			return fmt.Errorf("error patching memory: %w", err)
+		}
+	case "memory.delete":
+		var payload DeletePayload
+		err := envelope.decodePayload(&payload)
+		if err == nil {
+			_, err = s.Memory().DeleteMemory(ctx, payload.ID)
+		}
+		if ignoreNotFound(err) != nil {
+			return fmt.Errorf("error deleting memory: %w", err)
+		}
+	case "memory.delete_by_user":
+		var payload DeleteByUserPayload
+		if err := envelope.decodePayload(&payload); err != nil {
+			return fmt.Errorf("error deleting memories: %w", err)
+		}
+		deleted, err := deleteMemoriesOfUser(ctx, s, payload.UserID)
+		if err != nil {
+			return fmt.Errorf("error deleting memories of user %s after %d: %w", payload.UserID, deleted, err)
 		}
+		log.Printf("deleted %d memories of user %s", deleted, payload.UserID)
+	default:
+		return unknownOperation(envelope)
 	}
+	return nil
 }
@@ -0,0 +1,57 @@
+package consumer
+
+import (
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// offsetTracker keeps the fetched messages of each partition that are not
+// committed yet. Messages complete out of order when they are processed
+// concurrently, but an offset may only be committed once every message
+// before it is processed.
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending map[int][]*trackedMessage // By partition, in fetch order
+}
+
+type trackedMessage struct {
+	msg  kafka.Message
+	done bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{pending: make(map[int][]*trackedMessage)}
+}
+
+// add records a fetched message.
+func (t *offsetTracker) add(msg kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[msg.Partition] = append(t.pending[msg.Partition], &trackedMessage{msg: msg})
+}
+
+// complete marks msg as processed. It returns the last message of the
+// partition up to which all messages are processed, and false if that did
+// not advance.
+func (t *offsetTracker) complete(msg kafka.Message) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pending := t.pending[msg.Partition]
+	for _, m := range pending {
+		if m.msg.Offset == msg.Offset && !m.done {
+			m.done = true
+			break
+		}
+	}
+	n := 0
+	for n < len(pending) && pending[n].done {
+		n++
+	}
+	if n == 0 {
+		return kafka.Message{}, false
+	}
+	t.pending[msg.Partition] = pending[n:]
+	return pending[n-1].msg, true
+}
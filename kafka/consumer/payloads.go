@@ -0,0 +1,162 @@
+package consumer
+
+import (
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// Payloads of the create and update commands are the Create* and Update*
+// models; the payloads of the other commands follow.
+
+// MemoryPatch is the payload of memory.patch.
+type MemoryPatch struct {
+	models.PatchMemoryModel
+	// Fields lists the fields to change; listed fields the patch leaves out
+	// are reset to their zero value, except for coordinates, which cannot be
+	// reset. Without Fields the fields present in the patch are changed.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// MediaPatch is the payload of media.patch. Fields is as in MemoryPatch.
+type MediaPatch struct {
+	models.PatchMediaModel
+	Fields []string `json:"fields,omitempty"`
+}
+
+// CommentPatch is the payload of comment.patch. Fields is as in MemoryPatch.
+type CommentPatch struct {
+	models.PatchCommentModel
+	Fields []string `json:"fields,omitempty"`
+}
+
+// DeletePayload is the payload of memory.delete, media.delete and
+// comment.delete.
+type DeletePayload struct {
+	ID string `json:"id"`
+}
+
+// DeleteByUserPayload is the payload of memory.delete_by_user and
+// comment.delete_by_user.
+type DeleteByUserPayload struct {
+	UserID string `json:"user_id"`
+}
+
+// DeleteByMemoryPayload is the payload of media.delete_by_memory and
+// comment.delete_by_memory.
+type DeleteByMemoryPayload struct {
+	MemoryID string `json:"memory_id"`
+}
+
+// model returns the patch p describes.
+func (p *MemoryPatch) model() (*models.PatchMemoryModel, error) {
+	patchModel := &p.PatchMemoryModel
+	if len(p.Fields) > 0 {
+		patchModel = &models.PatchMemoryModel{
+			ID:              p.ID,
+			ExpectedVersion: p.ExpectedVersion,
+			ChangedBy:       p.ChangedBy,
+		}
+		for _, field := range p.Fields {
+			switch field {
+			case "title":
+				patchModel.Title = orZero(p.Title)
+			case "description":
+				patchModel.Description = orZero(p.Description)
+			case "date":
+				patchModel.Date = orZero(p.Date)
+			case "tags":
+				patchModel.Tags = orZero(p.Tags)
+			case "latitude":
+				if p.Latitude == nil {
+					return nil, unresettable(field)
+				}
+				patchModel.Latitude = p.Latitude
+			case "longitude":
+				if p.Longitude == nil {
+					return nil, unresettable(field)
+				}
+				patchModel.Longitude = p.Longitude
+			case "place_name":
+				patchModel.PlaceName = orZero(p.PlaceName)
+			case "privacy":
+				patchModel.Privacy = orZero(p.Privacy)
+			case "language":
+				patchModel.Language = orZero(p.Language)
+			default:
+				return nil, unpatchable(field)
+			}
+		}
+	}
+	if *patchModel == (models.PatchMemoryModel{ID: p.ID, ExpectedVersion: p.ExpectedVersion, ChangedBy: p.ChangedBy}) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "patch changes no fields")
+	}
+	return patchModel, nil
+}
+
+// model returns the patch p describes.
+func (p *MediaPatch) model() (*models.PatchMediaModel, error) {
+	patchModel := &p.PatchMediaModel
+	if len(p.Fields) > 0 {
+		patchModel = &models.PatchMediaModel{ID: p.ID}
+		for _, field := range p.Fields {
+			switch field {
+			case "memory_id":
+				patchModel.MemoryID = orZero(p.MemoryID)
+			case "type":
+				patchModel.Type = orZero(p.Type)
+			case "url":
+				patchModel.URL = orZero(p.URL)
+			case "created_at":
+				patchModel.Created = orZero(p.Created)
+			default:
+				return nil, unpatchable(field)
+			}
+		}
+	}
+	if *patchModel == (models.PatchMediaModel{ID: p.ID}) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "patch changes no fields")
+	}
+	return patchModel, nil
+}
+
+// model returns the patch p describes.
+func (p *CommentPatch) model() (*models.PatchCommentModel, error) {
+	patchModel := &p.PatchCommentModel
+	if len(p.Fields) > 0 {
+		patchModel = &models.PatchCommentModel{ID: p.ID, ExpectedVersion: p.ExpectedVersion}
+		for _, field := range p.Fields {
+			switch field {
+			case "memory_id":
+				patchModel.MemoryID = orZero(p.MemoryID)
+			case "user_id":
+				patchModel.UserID = orZero(p.UserID)
+			case "content":
+				patchModel.Content = orZero(p.Content)
+			case "created_at":
+				patchModel.Created = orZero(p.Created)
+			default:
+				return nil, unpatchable(field)
+			}
+		}
+	}
+	if *patchModel == (models.PatchCommentModel{ID: p.ID, ExpectedVersion: p.ExpectedVersion}) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "patch changes no fields")
+	}
+	return patchModel, nil
+}
+
+// orZero returns v, or a pointer to the zero value if v is nil.
+func orZero[T any](v *T) *T {
+	if v == nil {
+		return new(T)
+	}
+	return v
+}
+
+func unpatchable(field string) error {
+	return storage.Errorf(storage.ErrInvalidArgument, "field %q cannot be patched", field)
+}
+
+func unresettable(field string) error {
+	return storage.Errorf(storage.ErrInvalidArgument, "field %q needs a value: it cannot be reset", field)
+}
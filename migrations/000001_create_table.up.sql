@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS memories (
+    id          UUID PRIMARY KEY,
+    user_id     UUID NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    date        TIMESTAMPTZ NOT NULL,
+    tags        TEXT[] DEFAULT '{}',
+    latitude    DOUBLE PRECISION,
+    longitude   DOUBLE PRECISION,
+    place_name  VARCHAR(255) NOT NULL DEFAULT '',
+    privacy     VARCHAR(20) NOT NULL DEFAULT 'private',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_memories_user_id ON memories (user_id);
+CREATE INDEX IF NOT EXISTS idx_memories_date ON memories (date);
+CREATE INDEX IF NOT EXISTS idx_memories_tags ON memories USING GIN (tags);
+
+CREATE TABLE IF NOT EXISTS media (
+    id         UUID PRIMARY KEY,
+    memory_id  UUID NOT NULL REFERENCES memories (id) ON DELETE CASCADE,
+    type       VARCHAR(50) NOT NULL,
+    url        TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_memory_id ON media (memory_id);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         UUID PRIMARY KEY,
+    memory_id  UUID NOT NULL REFERENCES memories (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL,
+    content    TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_memory_id ON comments (memory_id);
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments (user_id);
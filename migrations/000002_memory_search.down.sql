@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_memories_search_vector;
+DROP TRIGGER IF EXISTS memories_search_vector_trigger ON memories;
+DROP FUNCTION IF EXISTS memories_search_vector_update();
+ALTER TABLE memories DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE memories DROP COLUMN IF EXISTS language;
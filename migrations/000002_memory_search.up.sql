@@ -0,0 +1,24 @@
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS language REGCONFIG NOT NULL DEFAULT 'english';
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- Title matches rank highest, then tags, description and place name.
+CREATE OR REPLACE FUNCTION memories_search_vector_update() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector(NEW.language, coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector(NEW.language, coalesce(array_to_string(NEW.tags, ' '), '')), 'B') ||
+        setweight(to_tsvector(NEW.language, coalesce(NEW.description, '')), 'C') ||
+        setweight(to_tsvector(NEW.language, coalesce(NEW.place_name, '')), 'D');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS memories_search_vector_trigger ON memories;
+CREATE TRIGGER memories_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF title, description, tags, place_name, language ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_search_vector_update();
+
+-- Backfill existing rows through the trigger.
+UPDATE memories SET title = title;
+
+CREATE INDEX IF NOT EXISTS idx_memories_search_vector ON memories USING GIN (search_vector);
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_memories_location ON memories (latitude, longitude)
+    WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
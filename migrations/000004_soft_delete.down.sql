@@ -0,0 +1,11 @@
+DELETE FROM comments WHERE deleted_at IS NOT NULL;
+DELETE FROM media WHERE deleted_at IS NOT NULL;
+DELETE FROM memories WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_comments_deleted_at;
+DROP INDEX IF EXISTS idx_media_deleted_at;
+DROP INDEX IF EXISTS idx_memories_deleted_at;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE media DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE memories DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,7 @@
+ALTER TABLE comments ALTER COLUMN updated_at DROP NOT NULL;
+ALTER TABLE comments ALTER COLUMN updated_at DROP DEFAULT;
+ALTER TABLE memories ALTER COLUMN updated_at DROP NOT NULL;
+ALTER TABLE memories ALTER COLUMN updated_at DROP DEFAULT;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS version;
+ALTER TABLE memories DROP COLUMN IF EXISTS version;
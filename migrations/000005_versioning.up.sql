@@ -0,0 +1,11 @@
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- Rows written before updated_at was maintained count as untouched since creation.
+UPDATE memories SET updated_at = created_at WHERE updated_at IS NULL;
+UPDATE comments SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE memories ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE memories ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE comments ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE comments ALTER COLUMN updated_at SET NOT NULL;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS memories_revision_trigger ON memories;
+DROP FUNCTION IF EXISTS memories_record_revision();
+DROP TABLE IF EXISTS memory_revisions;
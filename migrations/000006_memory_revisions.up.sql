@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS memory_revisions (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    memory_id      UUID NOT NULL REFERENCES memories (id) ON DELETE CASCADE,
+    version        BIGINT NOT NULL,
+    changed_by     UUID,
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    old_values     JSONB NOT NULL DEFAULT '{}',
+    new_values     JSONB NOT NULL DEFAULT '{}',
+    snapshot       JSONB NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_revisions_memory_id ON memory_revisions (memory_id, created_at DESC);
+
+-- Records every insert and update of a memory as a revision holding the
+-- changed fields and a snapshot of the whole row. Bookkeeping columns are not
+-- tracked. The editor comes from the transaction setting
+-- memory_service.changed_by and defaults to the memory owner.
+CREATE OR REPLACE FUNCTION memories_record_revision() RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB := '{}';
+    new_row JSONB := to_jsonb(NEW) - 'id' - 'search_vector' - 'updated_at' - 'version';
+    changed TEXT[];
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        old_row := to_jsonb(OLD) - 'id' - 'search_vector' - 'updated_at' - 'version';
+    END IF;
+
+    SELECT coalesce(array_agg(n.key ORDER BY n.key), '{}')
+    INTO changed
+    FROM jsonb_each(new_row) n
+    WHERE old_row -> n.key IS DISTINCT FROM n.value;
+
+    IF TG_OP = 'UPDATE' AND cardinality(changed) = 0 THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO memory_revisions (memory_id, version, changed_by, changed_fields, old_values, new_values, snapshot)
+    VALUES (
+        NEW.id,
+        NEW.version,
+        coalesce(NULLIF(current_setting('memory_service.changed_by', true), '')::uuid, NEW.user_id),
+        changed,
+        (SELECT coalesce(jsonb_object_agg(f, old_row -> f), '{}') FROM unnest(changed) AS f WHERE old_row ? f),
+        (SELECT coalesce(jsonb_object_agg(f, new_row -> f), '{}') FROM unnest(changed) AS f),
+        to_jsonb(NEW) - 'search_vector'
+    );
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS memories_revision_trigger ON memories;
+CREATE TRIGGER memories_revision_trigger
+    AFTER INSERT OR UPDATE ON memories
+    FOR EACH ROW EXECUTE FUNCTION memories_record_revision();
+
+-- Existing memories start their history with their current content.
+INSERT INTO memory_revisions (memory_id, version, changed_by, snapshot, created_at)
+SELECT id, version, user_id, to_jsonb(memories) - 'search_vector', updated_at
+FROM memories;
@@ -0,0 +1,10 @@
+-- Ids of the Kafka commands that have been applied. A command is recorded in
+-- the transaction of its writes, so a redelivered command is recognized and
+-- skipped.
+CREATE TABLE IF NOT EXISTS processed_commands (
+    id           TEXT PRIMARY KEY,
+    operation    TEXT NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_commands_processed_at ON processed_commands (processed_at);
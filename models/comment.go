@@ -17,7 +17,10 @@ type UpdateCommentModel struct {
 	MemoryID string    `json:"memory_id" bson:"memory_id"`
 	UserID   string    `json:"user_id" bson:"user_id"`
 	Content  string    `json:"content" bson:"content"`
-	Created  time.Time `json:"created_at" bson:"created_at"`
+	Created  time.Time `json:"created_at" bson:"created_at"` // Zero keeps the stored creation time
+	// ExpectedVersion, when non-zero, makes the update fail with a conflict
+	// unless the stored comment is still at that version.
+	ExpectedVersion int64 `json:"expected_version,omitempty" bson:"-"`
 }
 
 // PatchCommentModel represents the data structure for partially updating an existing comment (PATCH).
@@ -27,4 +30,7 @@ type PatchCommentModel struct {
 	UserID   *string    `json:"user_id,omitempty" bson:"user_id,omitempty"`
 	Content  *string    `json:"content,omitempty" bson:"content,omitempty"`
 	Created  *time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	// ExpectedVersion, when non-zero, makes the patch fail with a conflict
+	// unless the stored comment is still at that version.
+	ExpectedVersion int64 `json:"expected_version,omitempty" bson:"-"`
 }
@@ -17,7 +17,7 @@ type UpdateMediaModel struct {
 	MemoryID string    `json:"memory_id" bson:"memory_id"`
 	Type     string    `json:"type" bson:"type"`
 	URL      string    `json:"url" bson:"url"`
-	Created  time.Time `json:"created_at" bson:"created_at"`
+	Created  time.Time `json:"created_at" bson:"created_at"` // Zero keeps the stored creation time
 }
 
 // PatchMediaModel represents the data structure for partially updating an existing media entry (PATCH).
@@ -10,10 +10,11 @@ type CreateMemoryModel struct {
 	Description string    `json:"description" bson:"description"`
 	Date        time.Time `json:"date" bson:"date"`
 	Tags        []string  `json:"tags" bson:"tags"`
-	Latitude    float64   `json:"latitude" bson:"latitude"`
-	Longitude   float64   `json:"longitude" bson:"longitude"`
+	Latitude    *float64  `json:"latitude,omitempty" bson:"latitude,omitempty"`
+	Longitude   *float64  `json:"longitude,omitempty" bson:"longitude,omitempty"`
 	PlaceName   string    `json:"place_name" bson:"place_name"`
 	Privacy     string    `json:"privacy" bson:"privacy"`
+	Language    string    `json:"language,omitempty" bson:"language,omitempty"`
 }
 
 // UpdateMemoryModel represents the data structure for updating an existing memory (PUT).
@@ -24,10 +25,16 @@ type UpdateMemoryModel struct {
 	Description string    `json:"description" bson:"description"`
 	Date        time.Time `json:"date" bson:"date"`
 	Tags        []string  `json:"tags" bson:"tags"`
-	Latitude    float64   `json:"latitude" bson:"latitude"`
-	Longitude   float64   `json:"longitude" bson:"longitude"`
+	Latitude    *float64  `json:"latitude,omitempty" bson:"latitude,omitempty"`
+	Longitude   *float64  `json:"longitude,omitempty" bson:"longitude,omitempty"`
 	PlaceName   string    `json:"place_name" bson:"place_name"`
 	Privacy     string    `json:"privacy" bson:"privacy"`
+	Language    string    `json:"language,omitempty" bson:"language,omitempty"`
+	// ExpectedVersion, when non-zero, makes the update fail with a conflict
+	// unless the stored memory is still at that version.
+	ExpectedVersion int64 `json:"expected_version,omitempty" bson:"-"`
+	// ChangedBy is the user making the change, recorded in the revision history.
+	ChangedBy string `json:"changed_by,omitempty" bson:"-"`
 }
 
 // PatchMemoryModel represents the data structure for partially updating an existing memory (PATCH).
@@ -41,4 +48,10 @@ type PatchMemoryModel struct {
 	Longitude   *float64   `json:"longitude,omitempty" bson:"longitude,omitempty"`
 	PlaceName   *string    `json:"place_name,omitempty" bson:"place_name,omitempty"`
 	Privacy     *string    `json:"privacy,omitempty" bson:"privacy,omitempty"`
+	Language    *string    `json:"language,omitempty" bson:"language,omitempty"`
+	// ExpectedVersion, when non-zero, makes the patch fail with a conflict
+	// unless the stored memory is still at that version.
+	ExpectedVersion int64 `json:"expected_version,omitempty" bson:"-"`
+	// ChangedBy is the user making the change, recorded in the revision history.
+	ChangedBy string `json:"changed_by,omitempty" bson:"-"`
 }
@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/models"
 	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
 )
 
 // ... (MemoryService and MediaService code from previous responses)
@@ -23,26 +26,161 @@ func NewCommentService(storage storage.StorageI) *CommentService {
 	}
 }
 
+// CreateComment handles the CreateComment gRPC request.
+func (s *CommentService) CreateComment(ctx context.Context, req *memory.CreateCommentRequest) (*memory.Comment, error) {
+	model := &models.CreateCommentModel{
+		ID:       req.Id,
+		MemoryID: req.MemoryId,
+		UserID:   req.UserId,
+		Content:  req.Content,
+	}
+	if err := validation.CreateComment(model); err != nil {
+		return nil, err
+	}
+
+	id, err := s.storage.Comment().CreateComment(ctx, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	comment, err := s.storage.Comment().GetCommentByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// UpdateComment handles the UpdateComment gRPC request. An empty created_at
+// keeps the current creation time.
+func (s *CommentService) UpdateComment(ctx context.Context, req *memory.UpdateCommentRequest) (*memory.Comment, error) {
+	var created time.Time
+	if req.CreatedAt != "" {
+		var err error
+		if created, err = parseTime("created_at", req.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	model := &models.UpdateCommentModel{
+		ID:              req.Id,
+		MemoryID:        req.MemoryId,
+		UserID:          req.UserId,
+		Content:         req.Content,
+		Created:         created,
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	if err := validation.UpdateComment(model); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Comment().UpdateComment(ctx, model); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	comment, err := s.storage.Comment().GetCommentByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// PatchComment handles the PatchComment gRPC request.
+func (s *CommentService) PatchComment(ctx context.Context, req *memory.PatchCommentRequest) (*memory.Comment, error) {
+	patch, err := commentPatch(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.PatchComment(patch); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Comment().PatchComment(ctx, patch); err != nil {
+		return nil, fmt.Errorf("failed to patch comment: %w", err)
+	}
+
+	comment, err := s.storage.Comment().GetCommentByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get patched comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// commentPatch builds the patch for req. Without an update mask the fields
+// set in req are changed. With one, exactly the masked fields are changed and
+// those left unset are reset.
+func commentPatch(req *memory.PatchCommentRequest) (*models.PatchCommentModel, error) {
+	created, err := parseOptionalTime("created_at", req.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		return &models.PatchCommentModel{
+			ID:              req.Id,
+			MemoryID:        req.MemoryId,
+			UserID:          req.UserId,
+			Content:         req.Content,
+			Created:         created,
+			ExpectedVersion: req.ExpectedVersion,
+		}, nil
+	}
+
+	patch := &models.PatchCommentModel{
+		ID:              req.Id,
+		ExpectedVersion: req.ExpectedVersion,
+	}
+	for _, path := range req.UpdateMask.Paths {
+		switch path {
+		case "memory_id":
+			patch.MemoryID = orZero(req.MemoryId)
+		case "user_id":
+			patch.UserID = orZero(req.UserId)
+		case "content":
+			patch.Content = orZero(req.Content)
+		case "created_at":
+			patch.Created = orZero(created)
+		default:
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid update_mask field %q", path)
+		}
+	}
+	return patch, nil
+}
+
 // GetCommentByID handles the GetCommentByID gRPC request.
 func (s *CommentService) GetCommentByID(ctx context.Context, req *memory.GetCommentByIdRequest) (*memory.Comment, error) {
+	if err := validateReadMask(req.ReadMask, &memory.Comment{}); err != nil {
+		return nil, err
+	}
+
 	comment, err := s.storage.Comment().GetCommentByID(ctx, req.Id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comment by ID: %w", err)
 	}
 
+	applyReadMask(req.ReadMask, comment)
+
 	return comment, nil // Return the comment message directly
 }
 
 // GetAllComments handles the GetAllComments gRPC request.
 func (s *CommentService) GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	if err := validateReadMask(req.ReadMask, &memory.Comment{}); err != nil {
+		return nil, err
+	}
+
 	commentList, err := s.storage.Comment().GetAllComments(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all comments: %w", err)
 	}
 
-	return &memory.GetAllCommentsResponse{
-		Comments: commentList,
-	}, nil
+	for _, c := range commentList.Comments {
+		applyReadMask(req.ReadMask, c)
+	}
+
+	return commentList, nil
 }
 
 // DeleteComment handles the DeleteComment gRPC request.
@@ -51,5 +189,29 @@ func (s *CommentService) DeleteComment(ctx context.Context, req *memory.DeleteCo
 		return nil, fmt.Errorf("failed to delete comment: %w", err)
 	}
 
-	return &memory.DeleteCommentResponse{}, nil
+	return &memory.DeleteCommentResponse{Success: true}, nil
+}
+
+// GetDeletedComments handles the GetDeletedComments gRPC request.
+func (s *CommentService) GetDeletedComments(ctx context.Context, req *memory.GetDeletedCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	commentList, err := s.storage.Comment().GetDeletedComments(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted comments: %w", err)
+	}
+
+	return commentList, nil
+}
+
+// RestoreComment handles the RestoreComment gRPC request.
+func (s *CommentService) RestoreComment(ctx context.Context, req *memory.RestoreCommentRequest) (*memory.Comment, error) {
+	if err := s.storage.Comment().RestoreComment(ctx, req.Id); err != nil {
+		return nil, fmt.Errorf("failed to restore comment: %w", err)
+	}
+
+	comment, err := s.storage.Comment().GetCommentByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored comment: %w", err)
+	}
+
+	return comment, nil
 }
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the domain of the ErrorInfo details attached to errors.
+const errorDomain = "memory-service"
+
+// ErrorInterceptor translates the errors returned by the handlers into gRPC
+// status errors. Storage error kinds get their matching status code, and an
+// ErrorInfo detail whose reason names the kind; errors without a kind are
+// reported as internal errors. Validation errors also get a BadRequest detail
+// listing the field violations.
+func ErrorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return resp, nil
+}
+
+// toStatus converts err into a gRPC status error. Errors that already
+// carry a status are returned unchanged.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	code, reason := codes.Internal, "INTERNAL"
+	switch {
+	case errors.Is(err, storage.ErrMemoryNotFound):
+		code, reason = codes.NotFound, "MEMORY_NOT_FOUND"
+	case errors.Is(err, storage.ErrNotFound):
+		code, reason = codes.NotFound, "NOT_FOUND"
+	case errors.Is(err, storage.ErrAlreadyExists):
+		code, reason = codes.AlreadyExists, "ALREADY_EXISTS"
+	case errors.Is(err, storage.ErrInvalidArgument):
+		code, reason = codes.InvalidArgument, "INVALID_ARGUMENT"
+	case errors.Is(err, storage.ErrVersionConflict):
+		code, reason = codes.Aborted, "VERSION_CONFLICT"
+	case errors.Is(err, storage.ErrConflict):
+		code, reason = codes.Aborted, "CONFLICT"
+	case errors.Is(err, storage.ErrUnavailable):
+		code, reason = codes.Unavailable, "UNAVAILABLE"
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	}}
+	var invalid *validation.Error
+	if errors.As(err, &invalid) {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range invalid.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	st := status.New(code, err.Error())
+	detailed, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
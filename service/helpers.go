@@ -0,0 +1,75 @@
+package service
+
+import (
+	"time"
+
+	"github.com/time_capsule/memory-service/storage"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// parseTime parses an RFC3339 timestamp from the request field name.
+func parseTime(name, value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, storage.Errorf(storage.ErrInvalidArgument, "invalid %s format: %w", name, err)
+	}
+	return t, nil
+}
+
+// parseOptionalTime is parseTime for optional request fields; it returns nil
+// if value is unset.
+func parseOptionalTime(name string, value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := parseTime(name, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// orZero returns p, or a pointer to the zero value if p is nil. Patches use it
+// to reset masked fields the request leaves unset.
+func orZero[T any](p *T) *T {
+	if p != nil {
+		return p
+	}
+	var zero T
+	return &zero
+}
+
+// unresettable reports a masked field that needs a value because it cannot be
+// reset.
+func unresettable(field string) error {
+	return storage.Errorf(storage.ErrInvalidArgument, "update_mask field %q needs a value: it cannot be reset", field)
+}
+
+// validateReadMask checks that mask only names fields of msg.
+func validateReadMask(mask *fieldmaskpb.FieldMask, msg proto.Message) error {
+	if len(mask.GetPaths()) > 0 && !mask.IsValid(msg) {
+		return storage.Errorf(storage.ErrInvalidArgument, "invalid read_mask %v", mask.GetPaths())
+	}
+	return nil
+}
+
+// applyReadMask clears the fields of msg that mask does not name. An empty
+// mask keeps all fields.
+func applyReadMask(mask *fieldmaskpb.FieldMask, msg proto.Message) {
+	if len(mask.GetPaths()) == 0 {
+		return
+	}
+	keep := make(map[string]bool, len(mask.GetPaths()))
+	for _, path := range mask.GetPaths() {
+		keep[path] = true
+	}
+
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if field := fields.Get(i); !keep[string(field.Name())] {
+			m.Clear(field)
+		}
+	}
+}
@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/models"
 	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
 )
 
 // MediaService implements the gRPC server for media-related operations.
@@ -21,26 +24,156 @@ func NewMediaService(storage storage.StorageI) *MediaService {
 	}
 }
 
+// CreateMedia handles the CreateMedia gRPC request.
+func (s *MediaService) CreateMedia(ctx context.Context, req *memory.CreateMediaRequest) (*memory.Media, error) {
+	model := &models.CreateMediaModel{
+		ID:       req.Id,
+		MemoryID: req.MemoryId,
+		Type:     req.Type,
+		URL:      req.Url,
+	}
+	if err := validation.CreateMedia(model); err != nil {
+		return nil, err
+	}
+
+	id, err := s.storage.Media().CreateMedia(ctx, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create media: %w", err)
+	}
+
+	media, err := s.storage.Media().GetMediaByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created media: %w", err)
+	}
+
+	return media, nil
+}
+
+// UpdateMedia handles the UpdateMedia gRPC request. An empty created_at keeps
+// the current creation time.
+func (s *MediaService) UpdateMedia(ctx context.Context, req *memory.UpdateMediaRequest) (*memory.Media, error) {
+	var created time.Time
+	if req.CreatedAt != "" {
+		var err error
+		if created, err = parseTime("created_at", req.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	model := &models.UpdateMediaModel{
+		ID:       req.Id,
+		MemoryID: req.MemoryId,
+		Type:     req.Type,
+		URL:      req.Url,
+		Created:  created,
+	}
+	if err := validation.UpdateMedia(model); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Media().UpdateMedia(ctx, model); err != nil {
+		return nil, fmt.Errorf("failed to update media: %w", err)
+	}
+
+	media, err := s.storage.Media().GetMediaByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated media: %w", err)
+	}
+
+	return media, nil
+}
+
+// PatchMedia handles the PatchMedia gRPC request.
+func (s *MediaService) PatchMedia(ctx context.Context, req *memory.PatchMediaRequest) (*memory.Media, error) {
+	patch, err := mediaPatch(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.PatchMedia(patch); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Media().PatchMedia(ctx, patch); err != nil {
+		return nil, fmt.Errorf("failed to patch media: %w", err)
+	}
+
+	media, err := s.storage.Media().GetMediaByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get patched media: %w", err)
+	}
+
+	return media, nil
+}
+
+// mediaPatch builds the patch for req. Without an update mask the fields set
+// in req are changed. With one, exactly the masked fields are changed and
+// those left unset are reset.
+func mediaPatch(req *memory.PatchMediaRequest) (*models.PatchMediaModel, error) {
+	created, err := parseOptionalTime("created_at", req.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		return &models.PatchMediaModel{
+			ID:       req.Id,
+			MemoryID: req.MemoryId,
+			Type:     req.Type,
+			URL:      req.Url,
+			Created:  created,
+		}, nil
+	}
+
+	patch := &models.PatchMediaModel{ID: req.Id}
+	for _, path := range req.UpdateMask.Paths {
+		switch path {
+		case "memory_id":
+			patch.MemoryID = orZero(req.MemoryId)
+		case "type":
+			patch.Type = orZero(req.Type)
+		case "url":
+			patch.URL = orZero(req.Url)
+		case "created_at":
+			patch.Created = orZero(created)
+		default:
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid update_mask field %q", path)
+		}
+	}
+	return patch, nil
+}
+
 // GetMediaByID handles the GetMediaByID gRPC request.
 func (s *MediaService) GetMediaById(ctx context.Context, req *memory.GetMediaByIdRequest) (*memory.Media, error) {
+	if err := validateReadMask(req.ReadMask, &memory.Media{}); err != nil {
+		return nil, err
+	}
+
 	media, err := s.storage.Media().GetMediaByID(ctx, req.Id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get media by ID: %w", err)
 	}
 
+	applyReadMask(req.ReadMask, media)
+
 	return media, nil // Return the media message directly
 }
 
 // GetAllMedia handles the GetAllMedia gRPC request.
 func (s *MediaService) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) (*memory.GetAllMediaResponse, error) {
+	if err := validateReadMask(req.ReadMask, &memory.Media{}); err != nil {
+		return nil, err
+	}
+
 	mediaList, err := s.storage.Media().GetAllMedia(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all media: %w", err)
 	}
 
-	return &memory.GetAllMediaResponse{
-		Media: mediaList,
-	}, nil
+	for _, m := range mediaList.Media {
+		applyReadMask(req.ReadMask, m)
+	}
+
+	return mediaList, nil
 }
 
 // DeleteMedia handles the DeleteMedia gRPC request.
@@ -49,5 +182,29 @@ func (s *MediaService) DeleteMedia(ctx context.Context, req *memory.DeleteMediaR
 		return nil, fmt.Errorf("failed to delete media: %w", err)
 	}
 
-	return &memory.DeleteMediaResponse{}, nil
+	return &memory.DeleteMediaResponse{Success: true}, nil
+}
+
+// GetDeletedMedia handles the GetDeletedMedia gRPC request.
+func (s *MediaService) GetDeletedMedia(ctx context.Context, req *memory.GetDeletedMediaRequest) (*memory.GetAllMediaResponse, error) {
+	mediaList, err := s.storage.Media().GetDeletedMedia(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted media: %w", err)
+	}
+
+	return mediaList, nil
+}
+
+// RestoreMedia handles the RestoreMedia gRPC request.
+func (s *MediaService) RestoreMedia(ctx context.Context, req *memory.RestoreMediaRequest) (*memory.Media, error) {
+	if err := s.storage.Media().RestoreMedia(ctx, req.Id); err != nil {
+		return nil, fmt.Errorf("failed to restore media: %w", err)
+	}
+
+	media, err := s.storage.Media().GetMediaByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored media: %w", err)
+	}
+
+	return media, nil
 }
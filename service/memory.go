@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/models"
 	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
 )
 
 // MemoryService implements the gRPC server for memory-related operations.
@@ -21,34 +23,285 @@ func NewMemoryService(storage storage.StorageI) *MemoryService {
 	}
 }
 
+// CreateMemory handles the CreateMemory gRPC request.
+func (s *MemoryService) CreateMemory(ctx context.Context, req *memory.CreateMemoryRequest) (*memory.Memory, error) {
+	date, err := parseTime("date", req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &models.CreateMemoryModel{
+		ID:          req.Id,
+		UserID:      req.UserId,
+		Title:       req.Title,
+		Description: req.Description,
+		Date:        date,
+		Tags:        req.Tags,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		PlaceName:   req.PlaceName,
+		Privacy:     req.Privacy,
+		Language:    req.Language,
+	}
+	if err := validation.CreateMemory(model); err != nil {
+		return nil, err
+	}
+
+	id, err := s.storage.Memory().CreateMemory(ctx, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create memory: %w", err)
+	}
+
+	created, err := s.storage.Memory().GetMemoryByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created memory: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateMemory handles the UpdateMemory gRPC request.
+func (s *MemoryService) UpdateMemory(ctx context.Context, req *memory.UpdateMemoryRequest) (*memory.Memory, error) {
+	date, err := parseTime("date", req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &models.UpdateMemoryModel{
+		ID:              req.Id,
+		UserID:          req.UserId,
+		Title:           req.Title,
+		Description:     req.Description,
+		Date:            date,
+		Tags:            req.Tags,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		PlaceName:       req.PlaceName,
+		Privacy:         req.Privacy,
+		Language:        req.Language,
+		ExpectedVersion: req.ExpectedVersion,
+		ChangedBy:       req.ChangedBy,
+	}
+	if err := validation.UpdateMemory(model); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Memory().UpdateMemory(ctx, model); err != nil {
+		return nil, fmt.Errorf("failed to update memory: %w", err)
+	}
+
+	updated, err := s.storage.Memory().GetMemoryByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updated memory: %w", err)
+	}
+
+	return updated, nil
+}
+
+// PatchMemory handles the PatchMemory gRPC request.
+func (s *MemoryService) PatchMemory(ctx context.Context, req *memory.PatchMemoryRequest) (*memory.Memory, error) {
+	patch, err := memoryPatch(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.PatchMemory(patch); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Memory().PatchMemory(ctx, patch); err != nil {
+		return nil, fmt.Errorf("failed to patch memory: %w", err)
+	}
+
+	patched, err := s.storage.Memory().GetMemoryByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get patched memory: %w", err)
+	}
+
+	return patched, nil
+}
+
+// memoryPatch builds the patch for req. Without an update mask the fields set
+// in req are changed. With one, exactly the masked fields are changed and
+// those left unset are reset. Coordinates cannot be reset, so a masked
+// coordinate needs a value.
+func memoryPatch(req *memory.PatchMemoryRequest) (*models.PatchMemoryModel, error) {
+	date, err := parseOptionalTime("date", req.Date)
+	if err != nil {
+		return nil, err
+	}
+	var tags *[]string
+	if req.Tags != nil {
+		tags = &req.Tags.Tags
+	}
+
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		return &models.PatchMemoryModel{
+			ID:              req.Id,
+			Title:           req.Title,
+			Description:     req.Description,
+			Date:            date,
+			Tags:            tags,
+			Latitude:        req.Latitude,
+			Longitude:       req.Longitude,
+			PlaceName:       req.PlaceName,
+			Privacy:         req.Privacy,
+			Language:        req.Language,
+			ExpectedVersion: req.ExpectedVersion,
+			ChangedBy:       req.ChangedBy,
+		}, nil
+	}
+
+	patch := &models.PatchMemoryModel{
+		ID:              req.Id,
+		ExpectedVersion: req.ExpectedVersion,
+		ChangedBy:       req.ChangedBy,
+	}
+	for _, path := range req.UpdateMask.Paths {
+		switch path {
+		case "title":
+			patch.Title = orZero(req.Title)
+		case "description":
+			patch.Description = orZero(req.Description)
+		case "date":
+			patch.Date = orZero(date)
+		case "tags":
+			if tags == nil {
+				tags = &[]string{}
+			}
+			patch.Tags = tags
+		case "latitude":
+			if req.Latitude == nil {
+				return nil, unresettable(path)
+			}
+			patch.Latitude = req.Latitude
+		case "longitude":
+			if req.Longitude == nil {
+				return nil, unresettable(path)
+			}
+			patch.Longitude = req.Longitude
+		case "place_name":
+			patch.PlaceName = orZero(req.PlaceName)
+		case "privacy":
+			patch.Privacy = orZero(req.Privacy)
+		case "language":
+			patch.Language = orZero(req.Language)
+		default:
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid update_mask field %q", path)
+		}
+	}
+	return patch, nil
+}
+
 // GetMemoryByID handles the GetMemoryByID gRPC request.
 
 func (s *MemoryService) GetMemoryById(ctx context.Context, req *memory.GetMemoryByIdRequest) (*memory.Memory, error) {
+	if err := validateReadMask(req.ReadMask, &memory.Memory{}); err != nil {
+		return nil, err
+	}
+
 	memory, err := s.storage.Memory().GetMemoryByID(ctx, req.Id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get memory by ID: %w", err)
 	}
 
+	applyReadMask(req.ReadMask, memory)
 	return memory, nil
 }
 
 // GetAllMemories handles the GetAllMemories gRPC request.
 func (s *MemoryService) GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	if err := validateReadMask(req.ReadMask, &memory.Memory{}); err != nil {
+		return nil, err
+	}
+	if err := validation.GetAllMemories(req); err != nil {
+		return nil, err
+	}
+
 	memories, err := s.storage.Memory().GetAllMemories(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all memories: %w", err)
 	}
 
-	return &memory.GetAllMemoriesResponse{
-		Memories: memories,
-	}, nil
+	for _, m := range memories.Memories {
+		applyReadMask(req.ReadMask, m)
+	}
+
+	return memories, nil
 }
 
 // DeleteMemory handles the DeleteMemory gRPC request.
 func (s *MemoryService) DeleteMemory(ctx context.Context, req *memory.DeleteMemoryRequest) (*memory.DeleteMemoryResponse, error) {
-	if err := s.storage.Memory().DeleteMemory(ctx, req.Id); err != nil {
+	resp, err := s.storage.Memory().DeleteMemory(ctx, req.Id)
+	if err != nil {
 		return nil, fmt.Errorf("failed to delete memory: %w", err)
 	}
 
-	return &memory.DeleteMemoryResponse{}, nil
+	return resp, nil
+}
+
+// GetDeletedMemories handles the GetDeletedMemories gRPC request.
+func (s *MemoryService) GetDeletedMemories(ctx context.Context, req *memory.GetDeletedMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	memories, err := s.storage.Memory().GetDeletedMemories(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted memories: %w", err)
+	}
+
+	return memories, nil
+}
+
+// RestoreMemory handles the RestoreMemory gRPC request.
+func (s *MemoryService) RestoreMemory(ctx context.Context, req *memory.RestoreMemoryRequest) (*memory.Memory, error) {
+	if err := s.storage.Memory().RestoreMemory(ctx, req.Id); err != nil {
+		return nil, fmt.Errorf("failed to restore memory: %w", err)
+	}
+
+	restored, err := s.storage.Memory().GetMemoryByID(ctx, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored memory: %w", err)
+	}
+
+	return restored, nil
+}
+
+// ListMemoryRevisions handles the ListMemoryRevisions gRPC request.
+func (s *MemoryService) ListMemoryRevisions(ctx context.Context, req *memory.ListMemoryRevisionsRequest) (*memory.ListMemoryRevisionsResponse, error) {
+	revisions, err := s.storage.Memory().ListMemoryRevisions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memory revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetMemoryAsOf handles the GetMemoryAsOf gRPC request.
+func (s *MemoryService) GetMemoryAsOf(ctx context.Context, req *memory.GetMemoryAsOfRequest) (*memory.Memory, error) {
+	asOf, err := parseTime("as_of", req.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	memory, err := s.storage.Memory().GetMemoryAsOf(ctx, req.Id, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory as of %s: %w", req.AsOf, err)
+	}
+
+	return memory, nil
+}
+
+// RestoreMemoryRevision handles the RestoreMemoryRevision gRPC request.
+func (s *MemoryService) RestoreMemoryRevision(ctx context.Context, req *memory.RestoreMemoryRevisionRequest) (*memory.Memory, error) {
+	if err := validation.RestoreMemoryRevision(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Memory().RestoreMemoryRevision(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to restore memory revision: %w", err)
+	}
+
+	restored, err := s.storage.Memory().GetMemoryByID(ctx, req.MemoryId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restored memory: %w", err)
+	}
+
+	return restored, nil
 }
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// TrashPurger permanently deletes memories, media and comments that have
+// been in the trash for longer than the retention period.
+type TrashPurger struct {
+	storage   storage.StorageI
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewTrashPurger creates a new TrashPurger instance.
+func NewTrashPurger(storage storage.StorageI, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		storage:   storage,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run purges the trash every interval until the context is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Purge(ctx); err != nil {
+			log.Printf("error purging trash: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge hard-deletes everything trashed before now minus the retention period.
+func (p *TrashPurger) Purge(ctx context.Context) error {
+	before := time.Now().Add(-p.retention)
+
+	// Children first; memories take any remaining children with them.
+	comments, err := p.storage.Comment().PurgeComments(ctx, before)
+	if err != nil {
+		return err
+	}
+	media, err := p.storage.Media().PurgeMedia(ctx, before)
+	if err != nil {
+		return err
+	}
+	memories, err := p.storage.Memory().PurgeMemories(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	if memories+media+comments > 0 {
+		log.Printf("purged trash: %d memories, %d media, %d comments", memories, media, comments)
+	}
+	return nil
+}
+
+// CommandPurger forgets the processed Kafka commands that are older than the
+// retention period. Commands redelivered after that are applied again.
+type CommandPurger struct {
+	storage   storage.StorageI
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewCommandPurger creates a new CommandPurger instance.
+func NewCommandPurger(storage storage.StorageI, retention, interval time.Duration) *CommandPurger {
+	return &CommandPurger{
+		storage:   storage,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run purges processed commands every interval until the context is
+// cancelled.
+func (p *CommandPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Purge(ctx); err != nil {
+			log.Printf("error purging processed commands: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge forgets the commands processed before now minus the retention period.
+func (p *CommandPurger) Purge(ctx context.Context) error {
+	commands, err := p.storage.Command().PurgeCommands(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		return err
+	}
+
+	if commands > 0 {
+		log.Printf("purged %d processed commands", commands)
+	}
+	return nil
+}
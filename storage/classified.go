@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/models"
+)
+
+// classifiedMemory passes every call on to repo and classifies the error it
+// returns.
+type classifiedMemory struct {
+	repo       MemoryI
+	classifier Classifier
+}
+
+func (r *classifiedMemory) CreateMemory(ctx context.Context, memory *models.CreateMemoryModel) (string, error) {
+	resp, err := r.repo.CreateMemory(ctx, memory)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) BulkCreateMemories(ctx context.Context, memories []*models.CreateMemoryModel) ([]string, error) {
+	resp, err := r.repo.BulkCreateMemories(ctx, memories)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) GetMemoryByID(ctx context.Context, id string) (*memory.Memory, error) {
+	resp, err := r.repo.GetMemoryByID(ctx, id)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	resp, err := r.repo.GetAllMemories(ctx, req)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) UpdateMemory(ctx context.Context, memory *models.UpdateMemoryModel) error {
+	return r.classifier.Classify(r.repo.UpdateMemory(ctx, memory))
+}
+
+func (r *classifiedMemory) PatchMemory(ctx context.Context, memory *models.PatchMemoryModel) error {
+	return r.classifier.Classify(r.repo.PatchMemory(ctx, memory))
+}
+
+func (r *classifiedMemory) DeleteMemory(ctx context.Context, id string) (*memory.DeleteMemoryResponse, error) {
+	resp, err := r.repo.DeleteMemory(ctx, id)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) GetDeletedMemories(ctx context.Context, req *memory.GetDeletedMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	resp, err := r.repo.GetDeletedMemories(ctx, req)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) RestoreMemory(ctx context.Context, id string) error {
+	return r.classifier.Classify(r.repo.RestoreMemory(ctx, id))
+}
+
+func (r *classifiedMemory) PurgeMemories(ctx context.Context, before time.Time) (int64, error) {
+	resp, err := r.repo.PurgeMemories(ctx, before)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) ListMemoryRevisions(ctx context.Context, req *memory.ListMemoryRevisionsRequest) (*memory.ListMemoryRevisionsResponse, error) {
+	resp, err := r.repo.ListMemoryRevisions(ctx, req)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) GetMemoryAsOf(ctx context.Context, id string, asOf time.Time) (*memory.Memory, error) {
+	resp, err := r.repo.GetMemoryAsOf(ctx, id, asOf)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMemory) RestoreMemoryRevision(ctx context.Context, req *memory.RestoreMemoryRevisionRequest) error {
+	return r.classifier.Classify(r.repo.RestoreMemoryRevision(ctx, req))
+}
+
+// classifiedMedia passes every call on to repo and classifies the error it
+// returns.
+type classifiedMedia struct {
+	repo       MediaI
+	classifier Classifier
+}
+
+func (r *classifiedMedia) CreateMedia(ctx context.Context, media *models.CreateMediaModel) (string, error) {
+	resp, err := r.repo.CreateMedia(ctx, media)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMedia) BulkCreateMedia(ctx context.Context, media []*models.CreateMediaModel) ([]string, error) {
+	resp, err := r.repo.BulkCreateMedia(ctx, media)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMedia) GetMediaByID(ctx context.Context, id string) (*memory.Media, error) {
+	resp, err := r.repo.GetMediaByID(ctx, id)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMedia) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) (*memory.GetAllMediaResponse, error) {
+	resp, err := r.repo.GetAllMedia(ctx, req)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMedia) UpdateMedia(ctx context.Context, media *models.UpdateMediaModel) error {
+	return r.classifier.Classify(r.repo.UpdateMedia(ctx, media))
+}
+
+func (r *classifiedMedia) PatchMedia(ctx context.Context, media *models.PatchMediaModel) error {
+	return r.classifier.Classify(r.repo.PatchMedia(ctx, media))
+}
+
+func (r *classifiedMedia) DeleteMedia(ctx context.Context, id string) error {
+	return r.classifier.Classify(r.repo.DeleteMedia(ctx, id))
+}
+
+func (r *classifiedMedia) GetDeletedMedia(ctx context.Context, req *memory.GetDeletedMediaRequest) (*memory.GetAllMediaResponse, error) {
+	resp, err := r.repo.GetDeletedMedia(ctx, req)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedMedia) RestoreMedia(ctx context.Context, id string) error {
+	return r.classifier.Classify(r.repo.RestoreMedia(ctx, id))
+}
+
+func (r *classifiedMedia) PurgeMedia(ctx context.Context, before time.Time) (int64, error) {
+	resp, err := r.repo.PurgeMedia(ctx, before)
+	return resp, r.classifier.Classify(err)
+}
+
+// classifiedComment passes every call on to repo and classifies the error it
+// returns.
+type classifiedComment struct {
+	repo       CommentI
+	classifier Classifier
+}
+
+func (r *classifiedComment) CreateComment(ctx context.Context, comment *models.CreateCommentModel) (string, error) {
+	resp, err := r.repo.CreateComment(ctx, comment)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedComment) BulkCreateComments(ctx context.Context, comments []*models.CreateCommentModel) ([]string, error) {
+	resp, err := r.repo.BulkCreateComments(ctx, comments)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedComment) GetCommentByID(ctx context.Context, id string) (*memory.Comment, error) {
+	resp, err := r.repo.GetCommentByID(ctx, id)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedComment) GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	resp, err := r.repo.GetAllComments(ctx, req)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedComment) UpdateComment(ctx context.Context, comment *models.UpdateCommentModel) error {
+	return r.classifier.Classify(r.repo.UpdateComment(ctx, comment))
+}
+
+func (r *classifiedComment) PatchComment(ctx context.Context, comment *models.PatchCommentModel) error {
+	return r.classifier.Classify(r.repo.PatchComment(ctx, comment))
+}
+
+func (r *classifiedComment) DeleteComment(ctx context.Context, id string) error {
+	return r.classifier.Classify(r.repo.DeleteComment(ctx, id))
+}
+
+func (r *classifiedComment) GetDeletedComments(ctx context.Context, req *memory.GetDeletedCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	resp, err := r.repo.GetDeletedComments(ctx, req)
+	return resp, r.classifier.Classify(err)
+}
+
+func (r *classifiedComment) RestoreComment(ctx context.Context, id string) error {
+	return r.classifier.Classify(r.repo.RestoreComment(ctx, id))
+}
+
+func (r *classifiedComment) PurgeComments(ctx context.Context, before time.Time) (int64, error) {
+	resp, err := r.repo.PurgeComments(ctx, before)
+	return resp, r.classifier.Classify(err)
+}
+
+// classifiedCommand passes every call on to repo and classifies the error it
+// returns.
+type classifiedCommand struct {
+	repo       CommandI
+	classifier Classifier
+}
+
+func (r *classifiedCommand) MarkCommandProcessed(ctx context.Context, id, operation string) error {
+	return r.classifier.Classify(r.repo.MarkCommandProcessed(ctx, id, operation))
+}
+
+func (r *classifiedCommand) PurgeCommands(ctx context.Context, before time.Time) (int64, error) {
+	resp, err := r.repo.PurgeCommands(ctx, before)
+	return resp, r.classifier.Classify(err)
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/time_capsule/memory-service/helper"
+)
+
+// Error kinds. Every backend reports its failures as one of these, so callers
+// can tell them apart with errors.Is without knowing the driver. Errors
+// without a kind are internal errors.
+var (
+	// ErrNotFound is returned when an entity does not exist or is in the
+	// trash.
+	ErrNotFound = errors.New("not found")
+
+	// ErrAlreadyExists is returned when creating an entity whose id is
+	// taken.
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrInvalidArgument is returned for requests that can never succeed as
+	// they are, e.g. a malformed date or page token.
+	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrConflict is returned when a request clashes with the current state
+	// of the data, e.g. a concurrent write.
+	ErrConflict = errors.New("conflict")
+
+	// ErrUnavailable is returned when the database cannot be reached. The
+	// request may succeed when retried later.
+	ErrUnavailable = errors.New("storage unavailable")
+)
+
+var (
+	// ErrMemoryNotFound is returned when media or comments reference a memory
+	// that does not exist or is in the trash. It is an ErrNotFound.
+	ErrMemoryNotFound = Errorf(ErrNotFound, "memory not found")
+
+	// ErrVersionConflict is returned when an update carries an expected
+	// version that no longer matches the stored one, i.e. someone else wrote
+	// in between. It is an ErrConflict.
+	ErrVersionConflict = Errorf(ErrConflict, "version conflict")
+)
+
+// kinds lists the error kinds in the order Kind checks them.
+var kinds = []error{ErrNotFound, ErrAlreadyExists, ErrInvalidArgument, ErrConflict, ErrUnavailable}
+
+// kindError marks an error with its kind.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Errorf formats an error like fmt.Errorf and marks it with kind.
+func Errorf(kind error, format string, args ...interface{}) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// Wrap marks err with kind, prefixing its message with the kind. The driver
+// error stays available to errors.Is and errors.As.
+func Wrap(kind, err error) error {
+	return &kindError{kind: kind, err: fmt.Errorf("%s: %w", kind, err)}
+}
+
+// Kind returns the kind of err, or nil if it has none.
+func Kind(err error) error {
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// Classifier reports the kind of a backend specific error, or nil if it
+// does not recognize it. Every backend has one.
+type Classifier func(err error) error
+
+// Classify marks err with its kind. Errors that already have a kind,
+// context errors and errors the classifier does not recognize are returned
+// unchanged.
+func (c Classifier) Classify(err error) error {
+	if err == nil || Kind(err) != nil ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if errors.Is(err, helper.ErrInvalidPageToken) {
+		return Wrap(ErrInvalidArgument, err)
+	}
+	if kind := c(err); kind != nil {
+		return Wrap(kind, err)
+	}
+	return err
+}
+
+// Memory wraps repo so that it returns classified errors.
+func (c Classifier) Memory(repo MemoryI) MemoryI {
+	return &classifiedMemory{repo: repo, classifier: c}
+}
+
+// Media wraps repo so that it returns classified errors.
+func (c Classifier) Media(repo MediaI) MediaI {
+	return &classifiedMedia{repo: repo, classifier: c}
+}
+
+// Comment wraps repo so that it returns classified errors.
+func (c Classifier) Comment(repo CommentI) CommentI {
+	return &classifiedComment{repo: repo, classifier: c}
+}
+
+// Command wraps repo so that it returns classified errors.
+func (c Classifier) Command(repo CommandI) CommandI {
+	return &classifiedCommand{repo: repo, classifier: c}
+}
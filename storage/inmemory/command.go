@@ -0,0 +1,48 @@
+package inmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// commandRecord is a processed command.
+type commandRecord struct {
+	ID          string
+	Operation   string
+	ProcessedAt time.Time
+}
+
+type CommandRepo struct {
+	s *store
+}
+
+// MarkCommandProcessed records a processed command, rejecting one that was
+// recorded before.
+func (r *CommandRepo) MarkCommandProcessed(ctx context.Context, id, operation string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.commands[id]; ok {
+		return storage.Errorf(storage.ErrAlreadyExists, "command %s was already processed", id)
+	}
+	r.s.commands[id] = &commandRecord{ID: id, Operation: operation, ProcessedAt: now()}
+	return nil
+}
+
+// PurgeCommands forgets the commands processed before the given time.
+func (r *CommandRepo) PurgeCommands(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for id, command := range r.s.commands {
+		if command.ProcessedAt.Before(before) {
+			delete(r.s.commands, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,344 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// commentRecord is a stored comment.
+type commentRecord struct {
+	ID        string
+	MemoryID  string
+	UserID    string
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+	Version   int64
+}
+
+func (c *commentRecord) toProto() *memory.Comment {
+	return &memory.Comment{
+		Id:        c.ID,
+		MemoryId:  c.MemoryID,
+		UserId:    c.UserID,
+		Content:   c.Content,
+		CreatedAt: formatTime(&c.CreatedAt),
+		UpdatedAt: formatTime(&c.UpdatedAt),
+		DeletedAt: formatTime(c.DeletedAt),
+		Version:   c.Version,
+	}
+}
+
+type CommentRepo struct {
+	s *store
+}
+
+func (r *CommentRepo) CreateComment(ctx context.Context, comment *models.CreateCommentModel) (string, error) {
+	if comment.ID == "" {
+		comment.ID = uuid.NewString()
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.ensureMemoryExists(comment.MemoryID); err != nil {
+		return "", err
+	}
+	if _, ok := r.s.comments[comment.ID]; ok {
+		return "", storage.Errorf(storage.ErrAlreadyExists, "comment %s already exists", comment.ID)
+	}
+	r.s.insertComment(comment)
+
+	return comment.ID, nil
+}
+
+// BulkCreateComments checks all comments before creating any of them, so
+// either all are created or none.
+func (r *CommentRepo) BulkCreateComments(ctx context.Context, comments []*models.CreateCommentModel) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := make([]string, len(comments))
+	seen := make(map[string]bool, len(comments))
+	for i, comment := range comments {
+		if comment.ID == "" {
+			comment.ID = uuid.NewString()
+		}
+		if err := r.s.ensureMemoryExists(comment.MemoryID); err != nil {
+			return nil, err
+		}
+		if _, ok := r.s.comments[comment.ID]; ok || seen[comment.ID] {
+			return nil, storage.Errorf(storage.ErrAlreadyExists, "comment %s already exists", comment.ID)
+		}
+		seen[comment.ID] = true
+		ids[i] = comment.ID
+	}
+	for _, comment := range comments {
+		r.s.insertComment(comment)
+	}
+
+	return ids, nil
+}
+
+// insertComment stores a new comment. The caller must hold the lock.
+func (s *store) insertComment(comment *models.CreateCommentModel) {
+	ts := now()
+	s.comments[comment.ID] = &commentRecord{
+		ID:        comment.ID,
+		MemoryID:  comment.MemoryID,
+		UserID:    comment.UserID,
+		Content:   comment.Content,
+		CreatedAt: ts,
+		UpdatedAt: ts,
+		Version:   1,
+	}
+}
+
+func (r *CommentRepo) GetCommentByID(ctx context.Context, id string) (*memory.Comment, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	comment, ok := r.s.comments[id]
+	if !ok || comment.DeletedAt != nil {
+		return nil, storage.ErrNotFound
+	}
+
+	return comment.toProto(), nil
+}
+
+func (r *CommentRepo) GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	var cursor *helper.Cursor
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last comment of the
+		// previous page, so comments inserted meanwhile never shift the window.
+		var err error
+		cursor, err = helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		offset = 0
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var (
+		matches []*commentRecord
+		total   int32
+	)
+	for _, comment := range r.s.comments {
+		if comment.DeletedAt != nil {
+			continue
+		}
+		if req.MemoryId != "" && comment.MemoryID != req.MemoryId {
+			continue
+		}
+		if req.UserId != "" && comment.UserID != req.UserId {
+			continue
+		}
+		if req.Content != "" && !containsFold(comment.Content, req.Content) {
+			continue
+		}
+		total++
+		if cursor != nil && !before(comment.CreatedAt, comment.ID, cursor.Time, cursor.ID) {
+			continue
+		}
+		matches = append(matches, comment)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		return before(b.CreatedAt, b.ID, a.CreatedAt, a.ID)
+	})
+
+	// One extra comment tells us whether there is a next page.
+	matches = page(matches, offset, limit+1)
+
+	var (
+		commentList   []*memory.Comment
+		nextPageToken string
+	)
+	for _, comment := range matches {
+		if int32(len(commentList)) == limit {
+			last := matches[limit-1]
+			nextPageToken = helper.EncodeCursor(last.CreatedAt, last.ID)
+			break
+		}
+		commentList = append(commentList, comment.toProto())
+	}
+
+	return &memory.GetAllCommentsResponse{
+		Comments:      commentList,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (r *CommentRepo) UpdateComment(ctx context.Context, comment *models.UpdateCommentModel) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.ensureMemoryExists(comment.MemoryID); err != nil {
+		return err
+	}
+
+	stored, ok := r.s.comments[comment.ID]
+	if !ok || stored.DeletedAt != nil {
+		return storage.ErrNotFound
+	}
+	if err := checkVersion("comments", stored.ID, stored.Version, comment.ExpectedVersion); err != nil {
+		return err
+	}
+
+	stored.MemoryID = comment.MemoryID
+	stored.UserID = comment.UserID
+	stored.Content = comment.Content
+	if !comment.Created.IsZero() {
+		stored.CreatedAt = dbTime(comment.Created)
+	}
+	stored.UpdatedAt = now()
+	stored.Version++
+
+	return nil
+}
+
+func (r *CommentRepo) PatchComment(ctx context.Context, comment *models.PatchCommentModel) error {
+	if comment.MemoryID == nil && comment.UserID == nil && comment.Content == nil && comment.Created == nil {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if comment.MemoryID != nil {
+		if err := r.s.ensureMemoryExists(*comment.MemoryID); err != nil {
+			return err
+		}
+	}
+
+	stored, ok := r.s.comments[comment.ID]
+	if !ok || stored.DeletedAt != nil {
+		return storage.ErrNotFound
+	}
+	if err := checkVersion("comments", stored.ID, stored.Version, comment.ExpectedVersion); err != nil {
+		return err
+	}
+
+	if comment.MemoryID != nil {
+		stored.MemoryID = *comment.MemoryID
+	}
+
+	if comment.UserID != nil {
+		stored.UserID = *comment.UserID
+	}
+
+	if comment.Content != nil {
+		stored.Content = *comment.Content
+	}
+
+	if comment.Created != nil {
+		stored.CreatedAt = dbTime(*comment.Created)
+	}
+
+	// Every write bumps the version.
+	stored.UpdatedAt = now()
+	stored.Version++
+
+	return nil
+}
+
+// DeleteComment moves a comment to the trash.
+func (r *CommentRepo) DeleteComment(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	comment, ok := r.s.comments[id]
+	if !ok || comment.DeletedAt != nil {
+		return storage.ErrNotFound
+	}
+
+	ts := now()
+	comment.DeletedAt = &ts
+	comment.UpdatedAt = ts
+	comment.Version++
+	return nil
+}
+
+func (r *CommentRepo) GetDeletedComments(ctx context.Context, req *memory.GetDeletedCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var trashed []*commentRecord
+	for _, comment := range r.s.comments {
+		if comment.DeletedAt == nil {
+			continue
+		}
+		if req.MemoryId != "" && comment.MemoryID != req.MemoryId {
+			continue
+		}
+		if req.UserId != "" && comment.UserID != req.UserId {
+			continue
+		}
+		trashed = append(trashed, comment)
+	}
+	sort.Slice(trashed, func(i, j int) bool {
+		a, b := trashed[i], trashed[j]
+		return before(*b.DeletedAt, b.ID, *a.DeletedAt, a.ID)
+	})
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	var commentList []*memory.Comment
+	for _, comment := range page(trashed, offset, limit) {
+		commentList = append(commentList, comment.toProto())
+	}
+
+	return &memory.GetAllCommentsResponse{
+		Comments: commentList,
+		Count:    int32(len(trashed)),
+	}, nil
+}
+
+// RestoreComment takes a comment out of the trash. A comment whose memory is
+// still in the trash cannot be restored on its own.
+func (r *CommentRepo) RestoreComment(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	comment, ok := r.s.comments[id]
+	if !ok || comment.DeletedAt == nil {
+		return storage.ErrNotFound
+	}
+
+	if _, ok := r.s.liveMemory(comment.MemoryID); !ok {
+		return storage.Errorf(storage.ErrConflict, "cannot restore comment %s: its memory is in the trash", id)
+	}
+
+	comment.DeletedAt = nil
+	comment.UpdatedAt = now()
+	comment.Version++
+	return nil
+}
+
+// PurgeComments permanently deletes comments that were trashed before the given time.
+func (r *CommentRepo) PurgeComments(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for id, comment := range r.s.comments {
+		if comment.DeletedAt != nil && comment.DeletedAt.Before(before) {
+			delete(r.s.comments, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
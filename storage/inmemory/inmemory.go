@@ -0,0 +1,205 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// Storage implements the storage.StorageI interface in process memory. It
+// is safe for concurrent use and meant for tests and local development; it
+// mirrors the behaviour of the Postgres repositories, including the error
+// kinds they report.
+type Storage struct {
+	s        *store
+	MemoryS  storage.MemoryI
+	MediaS   storage.MediaI
+	CommentS storage.CommentI
+	CommandS storage.CommandI
+}
+
+// NewInMemoryStorage creates a new, empty in-memory storage instance.
+func NewInMemoryStorage() storage.StorageI {
+	return newStorage(newStore())
+}
+
+func newStorage(s *store) *Storage {
+	return &Storage{
+		s:        s,
+		MemoryS:  classifier.Memory(&MemoryRepo{s: s}),
+		MediaS:   classifier.Media(&MediaRepo{s: s}),
+		CommentS: classifier.Comment(&CommentRepo{s: s}),
+		CommandS: classifier.Command(&CommandRepo{s: s}),
+	}
+}
+
+// Memory returns the in-memory MemoryI implementation.
+func (s *Storage) Memory() storage.MemoryI {
+	return s.MemoryS
+}
+
+// Media returns the in-memory MediaI implementation.
+func (s *Storage) Media() storage.MediaI {
+	return s.MediaS
+}
+
+// Comment returns the in-memory CommentI implementation.
+func (s *Storage) Comment() storage.CommentI {
+	return s.CommentS
+}
+
+// Command returns the in-memory CommandI implementation.
+func (s *Storage) Command() storage.CommandI {
+	return s.CommandS
+}
+
+// WithTx runs fn against a private copy of the store, which replaces the
+// store if fn returns nil and is discarded if fn fails or panics. The store
+// stays locked meanwhile, so transactions are serialized with all other
+// access and fn must only use the storage it is given.
+func (s *Storage) WithTx(ctx context.Context, fn func(tx storage.StorageI) error) error {
+	s.s.mu.Lock()
+	defer s.s.mu.Unlock()
+
+	tx := s.s.clone()
+	if err := fn(newStorage(tx)); err != nil {
+		return err
+	}
+
+	s.s.memories = tx.memories
+	s.s.media = tx.media
+	s.s.comments = tx.comments
+	s.s.revisions = tx.revisions
+	s.s.commands = tx.commands
+	return nil
+}
+
+// store holds all entities behind a single lock, so cascading operations
+// across memories, media and comments are atomic like a Postgres
+// transaction.
+type store struct {
+	mu        sync.RWMutex
+	memories  map[string]*memoryRecord
+	media     map[string]*mediaRecord
+	comments  map[string]*commentRecord
+	revisions []*revisionRecord
+	commands  map[string]*commandRecord
+}
+
+func newStore() *store {
+	return &store{
+		memories: make(map[string]*memoryRecord),
+		media:    make(map[string]*mediaRecord),
+		comments: make(map[string]*commentRecord),
+		commands: make(map[string]*commandRecord),
+	}
+}
+
+// clone returns a deep copy of the store. The caller must hold the lock.
+func (s *store) clone() *store {
+	c := newStore()
+	for id, m := range s.memories {
+		c.memories[id] = m.clone()
+	}
+	for id, m := range s.media {
+		media := *m
+		c.media[id] = &media
+	}
+	for id, cm := range s.comments {
+		comment := *cm
+		c.comments[id] = &comment
+	}
+	// Revisions and processed commands are never changed once recorded,
+	// only dropped.
+	c.revisions = append([]*revisionRecord(nil), s.revisions...)
+	for id, command := range s.commands {
+		c.commands[id] = command
+	}
+	return c
+}
+
+// liveMemory returns the memory with the given id unless it is missing or
+// in the trash. The caller must hold the lock.
+func (s *store) liveMemory(id string) (*memoryRecord, bool) {
+	m, ok := s.memories[id]
+	if !ok || m.DeletedAt != nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// ensureMemoryExists returns storage.ErrMemoryNotFound unless the memory
+// exists and is not in the trash. The caller must hold the lock.
+func (s *store) ensureMemoryExists(id string) error {
+	if _, ok := s.liveMemory(id); !ok {
+		return fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, id)
+	}
+	return nil
+}
+
+// checkVersion returns storage.ErrVersionConflict when a non-zero expected
+// version does not match the current one.
+func checkVersion(kind, id string, current, expected int64) error {
+	if expected != 0 && current != expected {
+		return fmt.Errorf("%w: %s %s is at version %d, expected %d", storage.ErrVersionConflict, kind, id, current, expected)
+	}
+	return nil
+}
+
+// page returns the window of items selected by offset and limit.
+func page[T any](items []T, offset, limit int32) []T {
+	if int(offset) >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if int(limit) < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// before reports whether the (t, id) key sorts before the cursor in
+// descending (time, id) order, i.e. whether it belongs on a later page.
+func before(t time.Time, id string, cursorTime time.Time, cursorID string) bool {
+	return t.Before(cursorTime) || (t.Equal(cursorTime) && id < cursorID)
+}
+
+// now returns the current time at the microsecond precision Postgres
+// stores.
+func now() time.Time {
+	return dbTime(time.Now())
+}
+
+// dbTime returns t in UTC at the microsecond precision Postgres stores, so
+// values read back compare equal to what the Postgres repositories return.
+func dbTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// formatTime formats a time like helper.DateToString does for SQL NULL-able
+// columns: the zero or nil time becomes an empty string.
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// clonePtr returns a copy of the value p points to.
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// classifier classifies the errors of the in-memory repositories. They
+// create errors of the right kind themselves, so it only has to handle the
+// kinds shared by all backends.
+var classifier = storage.Classifier(func(err error) error {
+	return nil
+})
@@ -0,0 +1,315 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// mediaRecord is a stored media entry.
+type mediaRecord struct {
+	ID        string
+	MemoryID  string
+	Type      string
+	URL       string
+	CreatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (m *mediaRecord) toProto() *memory.Media {
+	return &memory.Media{
+		Id:        m.ID,
+		MemoryId:  m.MemoryID,
+		Type:      m.Type,
+		Url:       m.URL,
+		CreatedAt: formatTime(&m.CreatedAt),
+		DeletedAt: formatTime(m.DeletedAt),
+	}
+}
+
+type MediaRepo struct {
+	s *store
+}
+
+func (r *MediaRepo) CreateMedia(ctx context.Context, media *models.CreateMediaModel) (string, error) {
+	if media.ID == "" {
+		media.ID = uuid.NewString()
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.ensureMemoryExists(media.MemoryID); err != nil {
+		return "", err
+	}
+	if _, ok := r.s.media[media.ID]; ok {
+		return "", storage.Errorf(storage.ErrAlreadyExists, "media %s already exists", media.ID)
+	}
+	r.s.insertMedia(media)
+
+	return media.ID, nil
+}
+
+// BulkCreateMedia checks all media before creating any of them, so either
+// all are created or none.
+func (r *MediaRepo) BulkCreateMedia(ctx context.Context, media []*models.CreateMediaModel) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := make([]string, len(media))
+	seen := make(map[string]bool, len(media))
+	for i, m := range media {
+		if m.ID == "" {
+			m.ID = uuid.NewString()
+		}
+		if err := r.s.ensureMemoryExists(m.MemoryID); err != nil {
+			return nil, err
+		}
+		if _, ok := r.s.media[m.ID]; ok || seen[m.ID] {
+			return nil, storage.Errorf(storage.ErrAlreadyExists, "media %s already exists", m.ID)
+		}
+		seen[m.ID] = true
+		ids[i] = m.ID
+	}
+	for _, m := range media {
+		r.s.insertMedia(m)
+	}
+
+	return ids, nil
+}
+
+// insertMedia stores a new media entry. The caller must hold the lock.
+func (s *store) insertMedia(media *models.CreateMediaModel) {
+	s.media[media.ID] = &mediaRecord{
+		ID:        media.ID,
+		MemoryID:  media.MemoryID,
+		Type:      media.Type,
+		URL:       media.URL,
+		CreatedAt: now(),
+	}
+}
+
+func (r *MediaRepo) GetMediaByID(ctx context.Context, id string) (*memory.Media, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	media, ok := r.s.media[id]
+	if !ok || media.DeletedAt != nil {
+		return nil, storage.ErrNotFound
+	}
+
+	return media.toProto(), nil
+}
+
+func (r *MediaRepo) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) (*memory.GetAllMediaResponse, error) {
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	var cursor *helper.Cursor
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last entry of the
+		// previous page, so entries inserted meanwhile never shift the window.
+		var err error
+		cursor, err = helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		offset = 0
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var (
+		matches []*mediaRecord
+		total   int32
+	)
+	for _, media := range r.s.media {
+		if media.DeletedAt != nil {
+			continue
+		}
+		if req.MemoryId != "" && media.MemoryID != req.MemoryId {
+			continue
+		}
+		if req.Type != "" && !containsFold(media.Type, req.Type) {
+			continue
+		}
+		total++
+		if cursor != nil && !before(media.CreatedAt, media.ID, cursor.Time, cursor.ID) {
+			continue
+		}
+		matches = append(matches, media)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		return before(b.CreatedAt, b.ID, a.CreatedAt, a.ID)
+	})
+
+	// One extra entry tells us whether there is a next page.
+	matches = page(matches, offset, limit+1)
+
+	var (
+		mediaList     []*memory.Media
+		nextPageToken string
+	)
+	for _, media := range matches {
+		if int32(len(mediaList)) == limit {
+			last := matches[limit-1]
+			nextPageToken = helper.EncodeCursor(last.CreatedAt, last.ID)
+			break
+		}
+		mediaList = append(mediaList, media.toProto())
+	}
+
+	return &memory.GetAllMediaResponse{
+		Media:         mediaList,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (r *MediaRepo) UpdateMedia(ctx context.Context, media *models.UpdateMediaModel) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.ensureMemoryExists(media.MemoryID); err != nil {
+		return err
+	}
+
+	stored, ok := r.s.media[media.ID]
+	if !ok || stored.DeletedAt != nil {
+		return storage.ErrNotFound
+	}
+
+	stored.MemoryID = media.MemoryID
+	stored.Type = media.Type
+	stored.URL = media.URL
+	if !media.Created.IsZero() {
+		stored.CreatedAt = dbTime(media.Created)
+	}
+
+	return nil
+}
+
+func (r *MediaRepo) PatchMedia(ctx context.Context, media *models.PatchMediaModel) error {
+	if media.MemoryID == nil && media.Type == nil && media.URL == nil && media.Created == nil {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if media.MemoryID != nil {
+		if err := r.s.ensureMemoryExists(*media.MemoryID); err != nil {
+			return err
+		}
+	}
+
+	stored, ok := r.s.media[media.ID]
+	if !ok || stored.DeletedAt != nil {
+		return storage.ErrNotFound
+	}
+
+	if media.MemoryID != nil {
+		stored.MemoryID = *media.MemoryID
+	}
+
+	if media.Type != nil {
+		stored.Type = *media.Type
+	}
+
+	if media.URL != nil {
+		stored.URL = *media.URL
+	}
+
+	if media.Created != nil {
+		stored.CreatedAt = dbTime(*media.Created)
+	}
+
+	return nil
+}
+
+// DeleteMedia moves media to the trash.
+func (r *MediaRepo) DeleteMedia(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	media, ok := r.s.media[id]
+	if !ok || media.DeletedAt != nil {
+		return storage.ErrNotFound
+	}
+
+	ts := now()
+	media.DeletedAt = &ts
+	return nil
+}
+
+func (r *MediaRepo) GetDeletedMedia(ctx context.Context, req *memory.GetDeletedMediaRequest) (*memory.GetAllMediaResponse, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var trashed []*mediaRecord
+	for _, media := range r.s.media {
+		if media.DeletedAt == nil {
+			continue
+		}
+		if req.MemoryId != "" && media.MemoryID != req.MemoryId {
+			continue
+		}
+		trashed = append(trashed, media)
+	}
+	sort.Slice(trashed, func(i, j int) bool {
+		a, b := trashed[i], trashed[j]
+		return before(*b.DeletedAt, b.ID, *a.DeletedAt, a.ID)
+	})
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	var mediaList []*memory.Media
+	for _, media := range page(trashed, offset, limit) {
+		mediaList = append(mediaList, media.toProto())
+	}
+
+	return &memory.GetAllMediaResponse{
+		Media: mediaList,
+		Count: int32(len(trashed)),
+	}, nil
+}
+
+// RestoreMedia takes media out of the trash. Media whose memory is still
+// in the trash cannot be restored on its own.
+func (r *MediaRepo) RestoreMedia(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	media, ok := r.s.media[id]
+	if !ok || media.DeletedAt == nil {
+		return storage.ErrNotFound
+	}
+
+	if _, ok := r.s.liveMemory(media.MemoryID); !ok {
+		return storage.Errorf(storage.ErrConflict, "cannot restore media %s: its memory is in the trash", id)
+	}
+
+	media.DeletedAt = nil
+	return nil
+}
+
+// PurgeMedia permanently deletes media that were trashed before the given time.
+func (r *MediaRepo) PurgeMedia(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for id, media := range r.s.media {
+		if media.DeletedAt != nil && media.DeletedAt.Before(before) {
+			delete(r.s.media, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,902 @@
+package inmemory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// memoryRecord is a stored memory.
+type memoryRecord struct {
+	ID          string
+	UserID      string
+	Title       string
+	Description string
+	Date        time.Time
+	Tags        []string
+	Latitude    *float64
+	Longitude   *float64
+	PlaceName   string
+	Privacy     string
+	Language    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+	Version     int64
+}
+
+// clone returns a deep copy of m, so callers can change it without touching
+// the stored record.
+func (m *memoryRecord) clone() *memoryRecord {
+	c := *m
+	c.Tags = append([]string(nil), m.Tags...)
+	c.Latitude = clonePtr(m.Latitude)
+	c.Longitude = clonePtr(m.Longitude)
+	c.DeletedAt = clonePtr(m.DeletedAt)
+	return &c
+}
+
+func (m *memoryRecord) toProto() *memory.Memory {
+	return &memory.Memory{
+		Id:          m.ID,
+		UserId:      m.UserID,
+		Title:       m.Title,
+		Description: m.Description,
+		Date:        formatTime(&m.Date),
+		Tags:        append([]string(nil), m.Tags...),
+		Latitude:    clonePtr(m.Latitude),
+		Longitude:   clonePtr(m.Longitude),
+		PlaceName:   m.PlaceName,
+		Privacy:     m.Privacy,
+		Language:    m.Language,
+		CreatedAt:   formatTime(&m.CreatedAt),
+		UpdatedAt:   formatTime(&m.UpdatedAt),
+		DeletedAt:   formatTime(m.DeletedAt),
+		Version:     m.Version,
+	}
+}
+
+// revisionRecord is a recorded change of a memory. Old and new values are
+// JSON encoded.
+type revisionRecord struct {
+	ID            string
+	MemoryID      string
+	Version       int64
+	ChangedBy     string
+	ChangedFields []string
+	OldValues     map[string]string
+	NewValues     map[string]string
+	Snapshot      *memoryRecord
+	CreatedAt     time.Time
+}
+
+type MemoryRepo struct {
+	s *store
+}
+
+func (r *MemoryRepo) CreateMemory(ctx context.Context, memory *models.CreateMemoryModel) (string, error) {
+	if memory.ID == "" {
+		memory.ID = uuid.NewString()
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.memories[memory.ID]; ok {
+		return "", storage.Errorf(storage.ErrAlreadyExists, "memory %s already exists", memory.ID)
+	}
+	r.s.insertMemory(memory)
+
+	return memory.ID, nil
+}
+
+// BulkCreateMemories checks all memories before creating any of them, so
+// either all are created or none.
+func (r *MemoryRepo) BulkCreateMemories(ctx context.Context, memories []*models.CreateMemoryModel) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := make([]string, len(memories))
+	seen := make(map[string]bool, len(memories))
+	for i, memory := range memories {
+		if memory.ID == "" {
+			memory.ID = uuid.NewString()
+		}
+		if _, ok := r.s.memories[memory.ID]; ok || seen[memory.ID] {
+			return nil, storage.Errorf(storage.ErrAlreadyExists, "memory %s already exists", memory.ID)
+		}
+		seen[memory.ID] = true
+		ids[i] = memory.ID
+	}
+	for _, memory := range memories {
+		r.s.insertMemory(memory)
+	}
+
+	return ids, nil
+}
+
+// insertMemory stores a new memory and records its first revision. The
+// caller must hold the lock.
+func (s *store) insertMemory(memory *models.CreateMemoryModel) {
+	language := memory.Language
+	if language == "" {
+		language = defaultSearchLanguage
+	}
+
+	ts := now()
+	m := &memoryRecord{
+		ID:          memory.ID,
+		UserID:      memory.UserID,
+		Title:       memory.Title,
+		Description: memory.Description,
+		Date:        dbTime(memory.Date),
+		Tags:        append([]string(nil), memory.Tags...),
+		Latitude:    clonePtr(memory.Latitude),
+		Longitude:   clonePtr(memory.Longitude),
+		PlaceName:   memory.PlaceName,
+		Privacy:     memory.Privacy,
+		Language:    language,
+		CreatedAt:   ts,
+		UpdatedAt:   ts,
+		Version:     1,
+	}
+	s.memories[m.ID] = m
+	s.recordRevision(nil, m, "")
+}
+
+func (r *MemoryRepo) GetMemoryByID(ctx context.Context, id string) (*memory.Memory, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	m, ok := r.s.liveMemory(id)
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	return m.toProto(), nil
+}
+
+// memoryMatch is a memory selected by GetAllMemories together with its
+// computed ranking columns.
+type memoryMatch struct {
+	m        *memoryRecord
+	rank     float64
+	distance float64
+}
+
+func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	cond, err := memoryFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relevance and distance orderings are not keyset-friendly, so they are
+	// paginated with page/limit only.
+	less := func(a, b *memoryMatch) bool {
+		if !a.m.Date.Equal(b.m.Date) {
+			return a.m.Date.After(b.m.Date)
+		}
+		return a.m.ID > b.m.ID
+	}
+	keyset := true
+	switch req.SortBy {
+	case "", "date":
+	case "relevance":
+		if cond.search == nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "sort by relevance requires a search term")
+		}
+		byDate := less
+		less = func(a, b *memoryMatch) bool {
+			if a.rank != b.rank {
+				return a.rank > b.rank
+			}
+			return byDate(a, b)
+		}
+		keyset = false
+	case "distance":
+		if cond.center == nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "sort by distance requires a center point")
+		}
+		less = func(a, b *memoryMatch) bool {
+			if a.distance != b.distance {
+				return a.distance < b.distance
+			}
+			return a.m.ID > b.m.ID
+		}
+		keyset = false
+	default:
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid sort_by %q", req.SortBy)
+	}
+	if !keyset && req.PageToken != "" {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "page tokens are only supported when sorting by date")
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	var cursor *helper.Cursor
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last memory of the
+		// previous page, so memories inserted meanwhile never shift the window.
+		cursor, err = helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		offset = 0
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var (
+		matches []*memoryMatch
+		total   int32
+	)
+	for _, m := range r.s.memories {
+		match, ok := cond.match(m)
+		if !ok {
+			continue
+		}
+		total++
+		if cursor != nil && !before(m.Date, m.ID, cursor.Time, cursor.ID) {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	sort.Slice(matches, func(i, j int) bool { return less(matches[i], matches[j]) })
+
+	// One extra memory tells us whether there is a next page.
+	matches = page(matches, offset, limit+1)
+
+	var (
+		memories      []*memory.Memory
+		nextPageToken string
+	)
+	for _, match := range matches {
+		if int32(len(memories)) == limit {
+			if keyset {
+				last := matches[limit-1].m
+				nextPageToken = helper.EncodeCursor(last.Date, last.ID)
+			}
+			break
+		}
+		memoryModel := match.m.toProto()
+		memoryModel.SearchRank = match.rank
+		memoryModel.DistanceMeters = match.distance
+		if cond.search != nil {
+			memoryModel.Highlight = cond.search.highlight(match.m.Title + " " + match.m.Description)
+		}
+		memories = append(memories, memoryModel)
+	}
+
+	return &memory.GetAllMemoriesResponse{
+		Memories:      memories,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// memoryConditions holds the filter of a GetAllMemories request along with
+// what is needed to rank and measure the matches.
+type memoryConditions struct {
+	req         *memory.GetAllMemoriesRequest
+	search      *searchQuery // set when searching
+	emptySearch bool         // the search term consists of stop words only
+	startDate   *time.Time
+	endDate     *time.Time
+	center      *[2]float64 // latitude/longitude of the requested center point
+	radius      float64     // maximum distance from center, 0 for none
+	bbox        *[4]float64 // min latitude, min longitude, max latitude, max longitude
+}
+
+// memoryFilter validates a GetAllMemories request and prepares its filter.
+// It mirrors the Postgres filter semantics and error messages.
+func memoryFilter(req *memory.GetAllMemoriesRequest) (*memoryConditions, error) {
+	cond := &memoryConditions{req: req}
+
+	if req.SearchTerm != "" {
+		cond.search = parseSearchQuery(req.SearchTerm, req.SearchLanguage)
+		cond.emptySearch = cond.search == nil
+	}
+
+	if req.StartDate != "" {
+		startTime, err := time.Parse(time.RFC3339, req.StartDate)
+		if err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid start time format: %w", err)
+		}
+		cond.startDate = &startTime
+	}
+
+	if req.EndDate != "" {
+		endTime, err := time.Parse(time.RFC3339, req.EndDate)
+		if err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid end time format: %w", err)
+		}
+		cond.endDate = &endTime
+	}
+
+	if (req.CenterLatitude == nil) != (req.CenterLongitude == nil) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "center_latitude and center_longitude must be set together")
+	}
+	if req.RadiusMeters < 0 {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "radius_meters must not be negative")
+	}
+	if req.RadiusMeters > 0 && req.CenterLatitude == nil {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "radius_meters requires center_latitude and center_longitude")
+	}
+	if req.CenterLatitude != nil {
+		lat, lon := *req.CenterLatitude, *req.CenterLongitude
+		if err := helper.ValidateCoordinates(lat, lon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid center point: %w", err)
+		}
+		cond.center = &[2]float64{lat, lon}
+		cond.radius = req.RadiusMeters
+	}
+
+	bbox := []*float64{req.MinLatitude, req.MinLongitude, req.MaxLatitude, req.MaxLongitude}
+	bboxSet := 0
+	for _, v := range bbox {
+		if v != nil {
+			bboxSet++
+		}
+	}
+	if bboxSet != 0 && bboxSet != len(bbox) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "bounding box requires min/max latitude and longitude")
+	}
+	if bboxSet == len(bbox) {
+		minLat, minLon, maxLat, maxLon := *req.MinLatitude, *req.MinLongitude, *req.MaxLatitude, *req.MaxLongitude
+		if err := helper.ValidateCoordinates(minLat, minLon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: %w", err)
+		}
+		if err := helper.ValidateCoordinates(maxLat, maxLon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: %w", err)
+		}
+		if minLat > maxLat {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: min_latitude is greater than max_latitude")
+		}
+		cond.bbox = &[4]float64{minLat, minLon, maxLat, maxLon}
+	}
+
+	return cond, nil
+}
+
+// match reports whether m passes the filter and computes its search rank
+// and distance from the center point.
+func (c *memoryConditions) match(m *memoryRecord) (*memoryMatch, bool) {
+	req := c.req
+	match := &memoryMatch{m: m}
+
+	if m.DeletedAt != nil || c.emptySearch {
+		return nil, false
+	}
+
+	if c.search != nil {
+		rank, ok := c.search.rank(m)
+		if !ok {
+			return nil, false
+		}
+		match.rank = rank
+	}
+
+	if req.UserId != "" && m.UserID != req.UserId {
+		return nil, false
+	}
+
+	if req.Title != "" && !containsFold(m.Title, req.Title) {
+		return nil, false
+	}
+
+	if req.Description != "" && !containsFold(m.Description, req.Description) {
+		return nil, false
+	}
+
+	if len(req.Tags) > 0 && !overlaps(m.Tags, req.Tags) {
+		return nil, false
+	}
+
+	if c.startDate != nil && m.Date.Before(*c.startDate) {
+		return nil, false
+	}
+
+	if c.endDate != nil && m.Date.After(*c.endDate) {
+		return nil, false
+	}
+
+	// Like SQL comparisons, a missing coordinate never matches.
+	if req.Latitude != nil && (m.Latitude == nil || *m.Latitude != *req.Latitude) {
+		return nil, false
+	}
+
+	if req.Longitude != nil && (m.Longitude == nil || *m.Longitude != *req.Longitude) {
+		return nil, false
+	}
+
+	if c.center != nil || c.bbox != nil {
+		if m.Latitude == nil || m.Longitude == nil {
+			return nil, false
+		}
+	}
+
+	if c.center != nil {
+		match.distance = helper.HaversineDistance(c.center[0], c.center[1], *m.Latitude, *m.Longitude)
+		if c.radius > 0 && match.distance > c.radius {
+			return nil, false
+		}
+	}
+
+	if c.bbox != nil {
+		minLat, minLon, maxLat, maxLon := c.bbox[0], c.bbox[1], c.bbox[2], c.bbox[3]
+		lat, lon := *m.Latitude, *m.Longitude
+		if lat < minLat || lat > maxLat {
+			return nil, false
+		}
+		// A west edge greater than the east edge means the box crosses the
+		// antimeridian.
+		if minLon <= maxLon && (lon < minLon || lon > maxLon) {
+			return nil, false
+		}
+		if minLon > maxLon && lon < minLon && lon > maxLon {
+			return nil, false
+		}
+	}
+
+	if req.PlaceName != "" && !containsFold(m.PlaceName, req.PlaceName) {
+		return nil, false
+	}
+
+	if req.Privacy != "" && m.Privacy != req.Privacy {
+		return nil, false
+	}
+
+	return match, true
+}
+
+// containsFold reports whether s contains substr ignoring case, like
+// ILIKE '%substr%' in Postgres.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// overlaps reports whether a and b share an element, like the && array
+// operator in Postgres.
+func overlaps(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *MemoryRepo) UpdateMemory(ctx context.Context, memory *models.UpdateMemoryModel) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	m, ok := r.s.liveMemory(memory.ID)
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if err := checkVersion("memories", m.ID, m.Version, memory.ExpectedVersion); err != nil {
+		return err
+	}
+
+	updated := m.clone()
+	updated.UserID = memory.UserID
+	updated.Title = memory.Title
+	updated.Description = memory.Description
+	updated.Date = dbTime(memory.Date)
+	updated.Tags = append([]string(nil), memory.Tags...)
+	updated.Latitude = clonePtr(memory.Latitude)
+	updated.Longitude = clonePtr(memory.Longitude)
+	updated.PlaceName = memory.PlaceName
+	updated.Privacy = memory.Privacy
+	if memory.Language != "" {
+		updated.Language = memory.Language
+	}
+
+	r.s.updateMemory(m, updated, memory.ChangedBy)
+	return nil
+}
+
+func (r *MemoryRepo) PatchMemory(ctx context.Context, memory *models.PatchMemoryModel) error {
+	if memory.Title == nil && memory.Description == nil && memory.Date == nil &&
+		memory.Tags == nil && memory.Latitude == nil && memory.Longitude == nil &&
+		memory.PlaceName == nil && memory.Privacy == nil && memory.Language == nil {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	m, ok := r.s.liveMemory(memory.ID)
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if err := checkVersion("memories", m.ID, m.Version, memory.ExpectedVersion); err != nil {
+		return err
+	}
+
+	updated := m.clone()
+
+	if memory.Title != nil {
+		updated.Title = *memory.Title
+	}
+
+	if memory.Description != nil {
+		updated.Description = *memory.Description
+	}
+
+	if memory.Date != nil {
+		updated.Date = dbTime(*memory.Date)
+	}
+
+	if memory.Tags != nil {
+		updated.Tags = append([]string(nil), *memory.Tags...)
+	}
+
+	if memory.Latitude != nil {
+		updated.Latitude = clonePtr(memory.Latitude)
+	}
+
+	if memory.Longitude != nil {
+		updated.Longitude = clonePtr(memory.Longitude)
+	}
+
+	if memory.PlaceName != nil {
+		updated.PlaceName = *memory.PlaceName
+	}
+
+	if memory.Privacy != nil {
+		updated.Privacy = *memory.Privacy
+	}
+
+	if memory.Language != nil {
+		updated.Language = *memory.Language
+	}
+
+	r.s.updateMemory(m, updated, memory.ChangedBy)
+	return nil
+}
+
+// updateMemory replaces the stored memory old with updated, bumps its
+// version and records the change as a revision. The caller must hold the
+// write lock.
+func (s *store) updateMemory(old, updated *memoryRecord, changedBy string) {
+	updated.UpdatedAt = now()
+	updated.Version = old.Version + 1
+	s.memories[updated.ID] = updated
+	s.recordRevision(old, updated, changedBy)
+}
+
+// DeleteMemory moves a memory to the trash together with its media and
+// comments and reports how many children went with it. All of them share the
+// same deleted_at, so RestoreMemory can bring back exactly the children that
+// were trashed with the memory.
+func (r *MemoryRepo) DeleteMemory(ctx context.Context, id string) (*memory.DeleteMemoryResponse, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	m, ok := r.s.liveMemory(id)
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+
+	ts := now()
+	updated := m.clone()
+	updated.DeletedAt = &ts
+	r.s.updateMemory(m, updated, "")
+
+	resp := &memory.DeleteMemoryResponse{Success: true}
+	for _, media := range r.s.media {
+		if media.MemoryID == id && media.DeletedAt == nil {
+			media.DeletedAt = &ts
+			resp.DeletedMedia++
+		}
+	}
+	for _, comment := range r.s.comments {
+		if comment.MemoryID == id && comment.DeletedAt == nil {
+			comment.DeletedAt = &ts
+			comment.UpdatedAt = ts
+			comment.Version++
+			resp.DeletedComments++
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *MemoryRepo) GetDeletedMemories(ctx context.Context, req *memory.GetDeletedMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var trashed []*memoryRecord
+	for _, m := range r.s.memories {
+		if m.DeletedAt == nil {
+			continue
+		}
+		if req.UserId != "" && m.UserID != req.UserId {
+			continue
+		}
+		trashed = append(trashed, m)
+	}
+	sort.Slice(trashed, func(i, j int) bool {
+		a, b := trashed[i], trashed[j]
+		if !a.DeletedAt.Equal(*b.DeletedAt) {
+			return a.DeletedAt.After(*b.DeletedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	var memories []*memory.Memory
+	for _, m := range page(trashed, offset, limit) {
+		memories = append(memories, m.toProto())
+	}
+
+	return &memory.GetAllMemoriesResponse{
+		Memories: memories,
+		Count:    int32(len(trashed)),
+	}, nil
+}
+
+// RestoreMemory takes a memory out of the trash along with the media and
+// comments that were trashed together with it.
+func (r *MemoryRepo) RestoreMemory(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	m, ok := r.s.memories[id]
+	if !ok || m.DeletedAt == nil {
+		return storage.ErrNotFound
+	}
+
+	deletedAt := *m.DeletedAt
+	updated := m.clone()
+	updated.DeletedAt = nil
+	r.s.updateMemory(m, updated, "")
+
+	ts := now()
+	for _, media := range r.s.media {
+		if media.MemoryID == id && media.DeletedAt != nil && media.DeletedAt.Equal(deletedAt) {
+			media.DeletedAt = nil
+		}
+	}
+	for _, comment := range r.s.comments {
+		if comment.MemoryID == id && comment.DeletedAt != nil && comment.DeletedAt.Equal(deletedAt) {
+			comment.DeletedAt = nil
+			comment.UpdatedAt = ts
+			comment.Version++
+		}
+	}
+
+	return nil
+}
+
+// PurgeMemories permanently deletes memories that were trashed before the
+// given time together with their media, comments and revisions.
+func (r *MemoryRepo) PurgeMemories(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	purged := make(map[string]bool)
+	for id, m := range r.s.memories {
+		if m.DeletedAt != nil && m.DeletedAt.Before(before) {
+			purged[id] = true
+			delete(r.s.memories, id)
+		}
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	for id, media := range r.s.media {
+		if purged[media.MemoryID] {
+			delete(r.s.media, id)
+		}
+	}
+	for id, comment := range r.s.comments {
+		if purged[comment.MemoryID] {
+			delete(r.s.comments, id)
+		}
+	}
+	revisions := r.s.revisions[:0]
+	for _, revision := range r.s.revisions {
+		if !purged[revision.MemoryID] {
+			revisions = append(revisions, revision)
+		}
+	}
+	r.s.revisions = revisions
+
+	return int64(len(purged)), nil
+}
+
+// recordRevision stores the change from old to updated as a revision. A nil
+// old records the creation of the memory. Changes that touch no tracked
+// field are not recorded. The editor defaults to the memory owner. The
+// caller must hold the write lock.
+func (s *store) recordRevision(old, updated *memoryRecord, changedBy string) {
+	newFields := revisionFields(updated)
+	var oldFields map[string]string
+	if old != nil {
+		oldFields = revisionFields(old)
+	}
+
+	var changed []string
+	for field, value := range newFields {
+		if old == nil || oldFields[field] != value {
+			changed = append(changed, field)
+		}
+	}
+	if old != nil && len(changed) == 0 {
+		return
+	}
+	sort.Strings(changed)
+
+	oldValues := make(map[string]string)
+	newValues := make(map[string]string)
+	for _, field := range changed {
+		if old != nil {
+			oldValues[field] = oldFields[field]
+		}
+		newValues[field] = newFields[field]
+	}
+
+	if changedBy == "" {
+		changedBy = updated.UserID
+	}
+
+	s.revisions = append(s.revisions, &revisionRecord{
+		ID:            uuid.NewString(),
+		MemoryID:      updated.ID,
+		Version:       updated.Version,
+		ChangedBy:     changedBy,
+		ChangedFields: changed,
+		OldValues:     oldValues,
+		NewValues:     newValues,
+		Snapshot:      updated.clone(),
+		CreatedAt:     updated.UpdatedAt,
+	})
+}
+
+// revisionFields returns the JSON encoded fields of a memory that revisions
+// track. Bookkeeping fields are left out.
+func revisionFields(m *memoryRecord) map[string]string {
+	fields := map[string]interface{}{
+		"user_id":     m.UserID,
+		"title":       m.Title,
+		"description": m.Description,
+		"date":        m.Date,
+		"tags":        m.Tags,
+		"latitude":    m.Latitude,
+		"longitude":   m.Longitude,
+		"place_name":  m.PlaceName,
+		"privacy":     m.Privacy,
+		"language":    m.Language,
+		"created_at":  m.CreatedAt,
+		"deleted_at":  m.DeletedAt,
+	}
+
+	encoded := make(map[string]string, len(fields))
+	for field, value := range fields {
+		b, _ := json.Marshal(value)
+		encoded[field] = string(b)
+	}
+	return encoded
+}
+
+// revisionsOf returns the revisions of a memory, newest first. The caller
+// must hold the lock.
+func (s *store) revisionsOf(memoryID string) []*revisionRecord {
+	var revisions []*revisionRecord
+	for _, revision := range s.revisions {
+		if revision.MemoryID == memoryID {
+			revisions = append(revisions, revision)
+		}
+	}
+	sort.SliceStable(revisions, func(i, j int) bool {
+		a, b := revisions[i], revisions[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.Version > b.Version
+	})
+	return revisions
+}
+
+// ListMemoryRevisions lists the recorded changes of a memory, newest first.
+func (r *MemoryRepo) ListMemoryRevisions(ctx context.Context, req *memory.ListMemoryRevisionsRequest) (*memory.ListMemoryRevisionsResponse, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	revisions := r.s.revisionsOf(req.MemoryId)
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	var list []*memory.MemoryRevision
+	for _, rec := range page(revisions, offset, limit) {
+		revision := &memory.MemoryRevision{
+			Id:        rec.ID,
+			MemoryId:  rec.MemoryID,
+			Version:   rec.Version,
+			ChangedBy: rec.ChangedBy,
+			CreatedAt: formatTime(&rec.CreatedAt),
+		}
+		for _, field := range rec.ChangedFields {
+			revision.Changes = append(revision.Changes, &memory.FieldChange{
+				Field:    field,
+				OldValue: rec.OldValues[field],
+				NewValue: rec.NewValues[field],
+			})
+		}
+		list = append(list, revision)
+	}
+
+	return &memory.ListMemoryRevisionsResponse{
+		Revisions: list,
+		Count:     int32(len(revisions)),
+	}, nil
+}
+
+// GetMemoryAsOf returns the memory as it was at the given time, rebuilt from
+// the latest revision recorded at or before it.
+func (r *MemoryRepo) GetMemoryAsOf(ctx context.Context, id string, asOf time.Time) (*memory.Memory, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, revision := range r.s.revisionsOf(id) {
+		if !revision.CreatedAt.After(asOf) {
+			return revision.Snapshot.toProto(), nil
+		}
+	}
+
+	return nil, storage.ErrNotFound
+}
+
+// RestoreMemoryRevision writes the content of an earlier revision back to
+// the memory. The restore is itself recorded as a new revision, so nothing
+// in the history is lost.
+func (r *MemoryRepo) RestoreMemoryRevision(ctx context.Context, req *memory.RestoreMemoryRevisionRequest) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var revision *revisionRecord
+	for _, rec := range r.s.revisions {
+		if rec.ID == req.RevisionId && rec.MemoryID == req.MemoryId {
+			revision = rec
+			break
+		}
+	}
+	if revision == nil {
+		return storage.ErrNotFound
+	}
+
+	m, ok := r.s.liveMemory(req.MemoryId)
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if err := checkVersion("memories", m.ID, m.Version, req.ExpectedVersion); err != nil {
+		return err
+	}
+
+	snapshot := revision.Snapshot
+	updated := m.clone()
+	updated.Title = snapshot.Title
+	updated.Description = snapshot.Description
+	updated.Date = snapshot.Date
+	updated.Tags = append([]string(nil), snapshot.Tags...)
+	updated.Latitude = clonePtr(snapshot.Latitude)
+	updated.Longitude = clonePtr(snapshot.Longitude)
+	updated.PlaceName = snapshot.PlaceName
+	updated.Privacy = snapshot.Privacy
+	updated.Language = snapshot.Language
+
+	r.s.updateMemory(m, updated, req.ChangedBy)
+	return nil
+}
@@ -0,0 +1,186 @@
+package inmemory
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultSearchLanguage is the text search language used for search terms
+// that do not specify one, as in the Postgres repository.
+const defaultSearchLanguage = "english"
+
+// Field weights of the Postgres search vector as ts_rank_cd applies them:
+// title (A), tags (B), description (C) and place name (D).
+const (
+	weightTitle       = 1.0
+	weightTags        = 0.4
+	weightDescription = 0.2
+	weightPlaceName   = 0.1
+)
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// englishStopWords are dropped from documents and queries like the english
+// text search configuration does.
+var englishStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "have": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "our": true, "that": true, "the": true, "this": true, "to": true,
+	"was": true, "we": true, "were": true, "will": true, "with": true,
+}
+
+// englishSuffixes are stripped by stem, longest first.
+var englishSuffixes = []string{"ings", "ions", "ing", "ion", "ies", "ed", "es", "s"}
+
+// searchQuery is a parsed search term in websearch_to_tsquery syntax: groups
+// separated by "or", each a conjunction of terms, where a leading "-"
+// excludes a term.
+type searchQuery struct {
+	language string
+	groups   [][]searchTerm
+}
+
+type searchTerm struct {
+	lexeme string
+	negate bool
+}
+
+// parseSearchQuery parses a search term for the given language. It returns
+// nil if no term survives stop word removal, which matches nothing.
+func parseSearchQuery(term, language string) *searchQuery {
+	if language == "" {
+		language = defaultSearchLanguage
+	}
+	q := &searchQuery{language: language}
+
+	var group []searchTerm
+	for _, field := range strings.Fields(term) {
+		if strings.EqualFold(field, "or") {
+			if len(group) > 0 {
+				q.groups = append(q.groups, group)
+			}
+			group = nil
+			continue
+		}
+
+		negate := strings.HasPrefix(field, "-")
+		for _, word := range wordPattern.FindAllString(field, -1) {
+			if lexeme, ok := q.lexeme(word); ok {
+				group = append(group, searchTerm{lexeme: lexeme, negate: negate})
+			}
+		}
+	}
+	if len(group) > 0 {
+		q.groups = append(q.groups, group)
+	}
+
+	if len(q.groups) == 0 {
+		return nil
+	}
+	return q
+}
+
+// lexeme normalizes a word the way the text search configuration does. It
+// reports false for stop words.
+func (q *searchQuery) lexeme(word string) (string, bool) {
+	word = strings.ToLower(word)
+	if q.language == "simple" {
+		return word, true
+	}
+	if englishStopWords[word] {
+		return "", false
+	}
+	return stem(word), true
+}
+
+// stem strips common english suffixes so that e.g. "graduated",
+// "graduation" and "graduating" share the lexeme "graduat".
+func stem(word string) string {
+	for _, suffix := range englishSuffixes {
+		if len(word)-len(suffix) >= 3 && strings.HasSuffix(word, suffix) {
+			word = strings.TrimSuffix(word, suffix)
+			if suffix == "ies" {
+				word += "i"
+			}
+			return word
+		}
+	}
+	if len(word) > 3 && strings.HasSuffix(word, "y") {
+		return strings.TrimSuffix(word, "y") + "i"
+	}
+	return word
+}
+
+// lexemes returns the set of lexemes in text.
+func (q *searchQuery) lexemes(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range wordPattern.FindAllString(text, -1) {
+		if lexeme, ok := q.lexeme(word); ok {
+			set[lexeme] = true
+		}
+	}
+	return set
+}
+
+// rank reports whether m matches the query and how well, weighting each
+// matched term by the most important field it appears in.
+func (q *searchQuery) rank(m *memoryRecord) (float64, bool) {
+	fields := []struct {
+		lexemes map[string]bool
+		weight  float64
+	}{
+		{q.lexemes(m.Title), weightTitle},
+		{q.lexemes(strings.Join(m.Tags, " ")), weightTags},
+		{q.lexemes(m.Description), weightDescription},
+		{q.lexemes(m.PlaceName), weightPlaceName},
+	}
+	weight := func(lexeme string) float64 {
+		for _, f := range fields {
+			if f.lexemes[lexeme] {
+				return f.weight
+			}
+		}
+		return 0
+	}
+
+	best, matched := 0.0, false
+	for _, group := range q.groups {
+		score, ok := 0.0, true
+		for _, term := range group {
+			w := weight(term.lexeme)
+			if (w > 0) == term.negate {
+				ok = false
+				break
+			}
+			score += w
+		}
+		if ok {
+			matched = true
+			if score > best {
+				best = score
+			}
+		}
+	}
+	return best, matched
+}
+
+// highlight returns text with the words matching the query wrapped in <b>
+// tags, like ts_headline with its default options.
+func (q *searchQuery) highlight(text string) string {
+	wanted := make(map[string]bool)
+	for _, group := range q.groups {
+		for _, term := range group {
+			if !term.negate {
+				wanted[term.lexeme] = true
+			}
+		}
+	}
+
+	return wordPattern.ReplaceAllStringFunc(text, func(word string) string {
+		if lexeme, ok := q.lexeme(word); ok && wanted[lexeme] {
+			return "<b>" + word + "</b>"
+		}
+		return word
+	})
+}
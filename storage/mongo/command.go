@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/time_capsule/memory-service/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// commandDoc is the document stored in the processed commands collection.
+type commandDoc struct {
+	ID          string    `bson:"_id"`
+	Operation   string    `bson:"operation"`
+	ProcessedAt time.Time `bson:"processed_at"`
+}
+
+type CommandRepo struct {
+	db      *mongo.Database
+	session mongo.Session // set on repositories bound to a transaction
+}
+
+func NewCommandRepo(db *mongo.Database) *CommandRepo {
+	return &CommandRepo{
+		db: db,
+	}
+}
+
+func (r *CommandRepo) collection() *mongo.Collection {
+	return r.db.Collection(commandsCollection)
+}
+
+// MarkCommandProcessed records a processed command. It upserts rather than
+// inserts, because a duplicate key error would abort the surrounding
+// transaction; a command that was recorded before matches and is not
+// upserted.
+func (r *CommandRepo) MarkCommandProcessed(ctx context.Context, id, operation string) error {
+	ctx = withSession(ctx, r.session)
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$setOnInsert": &commandDoc{ID: id, Operation: operation, ProcessedAt: now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return err
+	}
+	if result.UpsertedCount == 0 {
+		return storage.Errorf(storage.ErrAlreadyExists, "command %s already processed", id)
+	}
+	return nil
+}
+
+// PurgeCommands forgets the commands processed before the given time.
+func (r *CommandRepo) PurgeCommands(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withSession(ctx, r.session)
+	result, err := r.collection().DeleteMany(ctx, bson.M{"processed_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
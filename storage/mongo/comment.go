@@ -0,0 +1,409 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// commentDoc is the document stored in the comments collection.
+type commentDoc struct {
+	ID        string     `bson:"_id"`
+	MemoryID  string     `bson:"memory_id"`
+	UserID    string     `bson:"user_id"`
+	Content   string     `bson:"content"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+	DeletedAt *time.Time `bson:"deleted_at"`
+	Version   int64      `bson:"version"`
+}
+
+func (d *commentDoc) toProto() *memory.Comment {
+	return &memory.Comment{
+		Id:        d.ID,
+		MemoryId:  d.MemoryID,
+		UserId:    d.UserID,
+		Content:   d.Content,
+		CreatedAt: formatTime(&d.CreatedAt),
+		UpdatedAt: formatTime(&d.UpdatedAt),
+		DeletedAt: formatTime(d.DeletedAt),
+		Version:   d.Version,
+	}
+}
+
+type CommentRepo struct {
+	db      *mongo.Database
+	session mongo.Session // set on repositories bound to a transaction
+}
+
+func NewCommentRepo(db *mongo.Database) *CommentRepo {
+	return &CommentRepo{
+		db: db,
+	}
+}
+
+func (r *CommentRepo) collection() *mongo.Collection {
+	return r.db.Collection(commentsCollection)
+}
+
+func (r *CommentRepo) CreateComment(ctx context.Context, comment *models.CreateCommentModel) (string, error) {
+	ctx = withSession(ctx, r.session)
+	if comment.ID == "" {
+		comment.ID = uuid.NewString()
+	}
+	if err := ensureMemoryExists(ctx, r.db, comment.MemoryID); err != nil {
+		return "", err
+	}
+
+	ts := now()
+	_, err := r.collection().InsertOne(ctx, &commentDoc{
+		ID:        comment.ID,
+		MemoryID:  comment.MemoryID,
+		UserID:    comment.UserID,
+		Content:   comment.Content,
+		CreatedAt: ts,
+		UpdatedAt: ts,
+		Version:   1,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return comment.ID, nil
+}
+
+// BulkCreateComments checks that the memories of the comments exist and
+// inserts the comments with one write, in a transaction.
+func (r *CommentRepo) BulkCreateComments(ctx context.Context, comments []*models.CreateCommentModel) ([]string, error) {
+	ids := make([]string, len(comments))
+	docs := make([]interface{}, len(comments))
+	var memoryIDs []string
+	seen := make(map[string]bool)
+	ts := now()
+	for i, comment := range comments {
+		if comment.ID == "" {
+			comment.ID = uuid.NewString()
+		}
+		if !seen[comment.MemoryID] {
+			seen[comment.MemoryID] = true
+			memoryIDs = append(memoryIDs, comment.MemoryID)
+		}
+		ids[i] = comment.ID
+		docs[i] = &commentDoc{
+			ID:        comment.ID,
+			MemoryID:  comment.MemoryID,
+			UserID:    comment.UserID,
+			Content:   comment.Content,
+			CreatedAt: ts,
+			UpdatedAt: ts,
+			Version:   1,
+		}
+	}
+	if len(docs) == 0 {
+		return ids, nil
+	}
+
+	err := inTx(ctx, r.db, r.session, func(ctx context.Context) error {
+		for _, memoryID := range memoryIDs {
+			if err := ensureMemoryExists(ctx, r.db, memoryID); err != nil {
+				return err
+			}
+		}
+		_, err := r.collection().InsertMany(ctx, docs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *CommentRepo) GetCommentByID(ctx context.Context, id string) (*memory.Comment, error) {
+	ctx = withSession(ctx, r.session)
+	var doc commentDoc
+	err := r.collection().FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.toProto(), nil
+}
+
+func (r *CommentRepo) GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	ctx = withSession(ctx, r.session)
+	filter := commentFilter(req)
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last document of the
+		// previous page, so documents inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": cursor.Time}},
+			bson.M{"created_at": cursor.Time, "_id": bson.M{"$lt": cursor.ID}},
+		}
+		offset = 0
+	}
+
+	// One extra document tells us whether there is a next page.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit) + 1)
+
+	cur, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var (
+		commentList   []*memory.Comment
+		lastCreatedAt time.Time
+		nextPageToken string
+	)
+
+	for cur.Next(ctx) {
+		var doc commentDoc
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		if int32(len(commentList)) == limit {
+			nextPageToken = helper.EncodeCursor(lastCreatedAt, commentList[len(commentList)-1].Id)
+			break
+		}
+		commentList = append(commentList, doc.toProto())
+		lastCreatedAt = doc.CreatedAt
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllCommentsResponse{
+		Comments:      commentList,
+		Count:         int32(total),
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// commentFilter builds the filter shared by the list and count queries of
+// GetAllComments.
+func commentFilter(req *memory.GetAllCommentsRequest) bson.M {
+	filter := bson.M{"deleted_at": nil}
+
+	if req.MemoryId != "" {
+		filter["memory_id"] = req.MemoryId
+	}
+
+	if req.UserId != "" {
+		filter["user_id"] = req.UserId
+	}
+
+	if req.Content != "" {
+		filter["content"] = containsPattern(req.Content)
+	}
+
+	return filter
+}
+
+func (r *CommentRepo) UpdateComment(ctx context.Context, comment *models.UpdateCommentModel) error {
+	ctx = withSession(ctx, r.session)
+	if err := ensureMemoryExists(ctx, r.db, comment.MemoryID); err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"memory_id":  comment.MemoryID,
+		"user_id":    comment.UserID,
+		"content":    comment.Content,
+		"updated_at": now(),
+	}
+	if !comment.Created.IsZero() {
+		set["created_at"] = comment.Created
+	}
+
+	filter := versionFilter(bson.M{"_id": comment.ID, "deleted_at": nil}, comment.ExpectedVersion)
+	result, err := r.collection().UpdateOne(ctx, filter, bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return missingOrConflict(ctx, r.db, commentsCollection, comment.ID, comment.ExpectedVersion)
+	}
+
+	return nil
+}
+
+func (r *CommentRepo) PatchComment(ctx context.Context, comment *models.PatchCommentModel) error {
+	ctx = withSession(ctx, r.session)
+	if comment.MemoryID != nil {
+		if err := ensureMemoryExists(ctx, r.db, *comment.MemoryID); err != nil {
+			return err
+		}
+	}
+
+	set := bson.M{}
+
+	if comment.MemoryID != nil {
+		set["memory_id"] = *comment.MemoryID
+	}
+
+	if comment.UserID != nil {
+		set["user_id"] = *comment.UserID
+	}
+
+	if comment.Content != nil {
+		set["content"] = *comment.Content
+	}
+
+	if comment.Created != nil {
+		set["created_at"] = *comment.Created
+	}
+
+	if len(set) == 0 {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	// Every write bumps the version; a non-zero expected version must match.
+	set["updated_at"] = now()
+	filter := versionFilter(bson.M{"_id": comment.ID, "deleted_at": nil}, comment.ExpectedVersion)
+	result, err := r.collection().UpdateOne(ctx, filter, bson.M{"$set": set, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return missingOrConflict(ctx, r.db, commentsCollection, comment.ID, comment.ExpectedVersion)
+	}
+
+	return nil
+}
+
+// DeleteComment moves a comment to the trash.
+func (r *CommentRepo) DeleteComment(ctx context.Context, id string) error {
+	ctx = withSession(ctx, r.session)
+	ts := now()
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": ts, "updated_at": ts}, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *CommentRepo) GetDeletedComments(ctx context.Context, req *memory.GetDeletedCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	ctx = withSession(ctx, r.session)
+	filter := bson.M{"deleted_at": bson.M{"$ne": nil}}
+	if req.MemoryId != "" {
+		filter["memory_id"] = req.MemoryId
+	}
+	if req.UserId != "" {
+		filter["user_id"] = req.UserId
+	}
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cur, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var commentList []*memory.Comment
+	for cur.Next(ctx) {
+		var doc commentDoc
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		commentList = append(commentList, doc.toProto())
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllCommentsResponse{
+		Comments: commentList,
+		Count:    int32(total),
+	}, nil
+}
+
+// RestoreComment takes a comment out of the trash. A comment whose memory is
+// still in the trash cannot be restored on its own.
+func (r *CommentRepo) RestoreComment(ctx context.Context, id string) error {
+	ctx = withSession(ctx, r.session)
+	var doc commentDoc
+	err := r.collection().FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}).Decode(&doc)
+	if err != nil {
+		return err
+	}
+
+	var parent memoryDoc
+	if err := r.db.Collection(memoriesCollection).FindOne(ctx, bson.M{"_id": doc.MemoryID}).Decode(&parent); err != nil {
+		return err
+	}
+	if parent.DeletedAt != nil {
+		return storage.Errorf(storage.ErrConflict, "cannot restore comment %s: its memory is in the trash", id)
+	}
+
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{"$set": bson.M{"deleted_at": nil, "updated_at": now()}, "$inc": bson.M{"version": 1}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// PurgeComments permanently deletes comments that were trashed before the given time.
+func (r *CommentRepo) PurgeComments(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withSession(ctx, r.session)
+	result, err := r.collection().DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$ne": nil, "$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,30 @@
+package mongo
+
+import (
+	"errors"
+
+	"github.com/time_capsule/memory-service/storage"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// classifier maps MongoDB errors onto the storage error kinds.
+var classifier = storage.Classifier(errorKind)
+
+func errorKind(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return storage.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return storage.ErrAlreadyExists
+	case mongo.IsNetworkError(err), mongo.IsTimeout(err), errors.Is(err, mongo.ErrClientDisconnected):
+		return storage.ErrUnavailable
+	}
+
+	// Write conflicts between concurrent transactions are labelled
+	// transient; the transaction can be retried.
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) && labeled.HasErrorLabel("TransientTransactionError") {
+		return storage.ErrConflict
+	}
+	return nil
+}
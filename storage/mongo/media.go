@@ -0,0 +1,387 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mediaDoc is the document stored in the media collection.
+type mediaDoc struct {
+	ID        string     `bson:"_id"`
+	MemoryID  string     `bson:"memory_id"`
+	Type      string     `bson:"type"`
+	URL       string     `bson:"url"`
+	CreatedAt time.Time  `bson:"created_at"`
+	DeletedAt *time.Time `bson:"deleted_at"`
+}
+
+func (d *mediaDoc) toProto() *memory.Media {
+	return &memory.Media{
+		Id:        d.ID,
+		MemoryId:  d.MemoryID,
+		Type:      d.Type,
+		Url:       d.URL,
+		CreatedAt: formatTime(&d.CreatedAt),
+		DeletedAt: formatTime(d.DeletedAt),
+	}
+}
+
+type MediaRepo struct {
+	db      *mongo.Database
+	session mongo.Session // set on repositories bound to a transaction
+}
+
+func NewMediaRepo(db *mongo.Database) *MediaRepo {
+	return &MediaRepo{
+		db: db,
+	}
+}
+
+func (r *MediaRepo) collection() *mongo.Collection {
+	return r.db.Collection(mediaCollection)
+}
+
+func (r *MediaRepo) CreateMedia(ctx context.Context, media *models.CreateMediaModel) (string, error) {
+	ctx = withSession(ctx, r.session)
+	if media.ID == "" {
+		media.ID = uuid.NewString()
+	}
+	if err := ensureMemoryExists(ctx, r.db, media.MemoryID); err != nil {
+		return "", err
+	}
+
+	_, err := r.collection().InsertOne(ctx, &mediaDoc{
+		ID:        media.ID,
+		MemoryID:  media.MemoryID,
+		Type:      media.Type,
+		URL:       media.URL,
+		CreatedAt: now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return media.ID, nil
+}
+
+// BulkCreateMedia checks that the memories of the media exist and inserts the
+// media with one write, in a transaction.
+func (r *MediaRepo) BulkCreateMedia(ctx context.Context, media []*models.CreateMediaModel) ([]string, error) {
+	ids := make([]string, len(media))
+	docs := make([]interface{}, len(media))
+	var memoryIDs []string
+	seen := make(map[string]bool)
+	ts := now()
+	for i, m := range media {
+		if m.ID == "" {
+			m.ID = uuid.NewString()
+		}
+		if !seen[m.MemoryID] {
+			seen[m.MemoryID] = true
+			memoryIDs = append(memoryIDs, m.MemoryID)
+		}
+		ids[i] = m.ID
+		docs[i] = &mediaDoc{
+			ID:        m.ID,
+			MemoryID:  m.MemoryID,
+			Type:      m.Type,
+			URL:       m.URL,
+			CreatedAt: ts,
+		}
+	}
+	if len(docs) == 0 {
+		return ids, nil
+	}
+
+	err := inTx(ctx, r.db, r.session, func(ctx context.Context) error {
+		for _, memoryID := range memoryIDs {
+			if err := ensureMemoryExists(ctx, r.db, memoryID); err != nil {
+				return err
+			}
+		}
+		_, err := r.collection().InsertMany(ctx, docs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *MediaRepo) GetMediaByID(ctx context.Context, id string) (*memory.Media, error) {
+	ctx = withSession(ctx, r.session)
+	var doc mediaDoc
+	err := r.collection().FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.toProto(), nil
+}
+
+func (r *MediaRepo) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) (*memory.GetAllMediaResponse, error) {
+	ctx = withSession(ctx, r.session)
+	filter := mediaFilter(req)
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last document of the
+		// previous page, so documents inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": cursor.Time}},
+			bson.M{"created_at": cursor.Time, "_id": bson.M{"$lt": cursor.ID}},
+		}
+		offset = 0
+	}
+
+	// One extra document tells us whether there is a next page.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit) + 1)
+
+	cur, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var (
+		mediaList     []*memory.Media
+		lastCreatedAt time.Time
+		nextPageToken string
+	)
+
+	for cur.Next(ctx) {
+		var doc mediaDoc
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		if int32(len(mediaList)) == limit {
+			nextPageToken = helper.EncodeCursor(lastCreatedAt, mediaList[len(mediaList)-1].Id)
+			break
+		}
+		mediaList = append(mediaList, doc.toProto())
+		lastCreatedAt = doc.CreatedAt
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMediaResponse{
+		Media:         mediaList,
+		Count:         int32(total),
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// mediaFilter builds the filter shared by the list and count queries of
+// GetAllMedia.
+func mediaFilter(req *memory.GetAllMediaRequest) bson.M {
+	filter := bson.M{"deleted_at": nil}
+
+	if req.MemoryId != "" {
+		filter["memory_id"] = req.MemoryId
+	}
+
+	if len(req.Type) > 0 {
+		filter["type"] = containsPattern(req.Type)
+	}
+
+	return filter
+}
+
+func (r *MediaRepo) UpdateMedia(ctx context.Context, media *models.UpdateMediaModel) error {
+	ctx = withSession(ctx, r.session)
+	if err := ensureMemoryExists(ctx, r.db, media.MemoryID); err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"memory_id": media.MemoryID,
+		"type":      media.Type,
+		"url":       media.URL,
+	}
+	if !media.Created.IsZero() {
+		set["created_at"] = media.Created
+	}
+
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": media.ID, "deleted_at": nil},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *MediaRepo) PatchMedia(ctx context.Context, media *models.PatchMediaModel) error {
+	ctx = withSession(ctx, r.session)
+	if media.MemoryID != nil {
+		if err := ensureMemoryExists(ctx, r.db, *media.MemoryID); err != nil {
+			return err
+		}
+	}
+
+	set := bson.M{}
+
+	if media.MemoryID != nil {
+		set["memory_id"] = *media.MemoryID
+	}
+
+	if media.Type != nil {
+		set["type"] = *media.Type
+	}
+
+	if media.URL != nil {
+		set["url"] = *media.URL
+	}
+
+	if media.Created != nil {
+		set["created_at"] = *media.Created
+	}
+
+	if len(set) == 0 {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	result, err := r.collection().UpdateOne(ctx, bson.M{"_id": media.ID, "deleted_at": nil}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// DeleteMedia moves media to the trash.
+func (r *MediaRepo) DeleteMedia(ctx context.Context, id string) error {
+	ctx = withSession(ctx, r.session)
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": nil},
+		bson.M{"$set": bson.M{"deleted_at": now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *MediaRepo) GetDeletedMedia(ctx context.Context, req *memory.GetDeletedMediaRequest) (*memory.GetAllMediaResponse, error) {
+	ctx = withSession(ctx, r.session)
+	filter := bson.M{"deleted_at": bson.M{"$ne": nil}}
+	if req.MemoryId != "" {
+		filter["memory_id"] = req.MemoryId
+	}
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cur, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var mediaList []*memory.Media
+	for cur.Next(ctx) {
+		var doc mediaDoc
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		mediaList = append(mediaList, doc.toProto())
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMediaResponse{
+		Media: mediaList,
+		Count: int32(total),
+	}, nil
+}
+
+// RestoreMedia takes media out of the trash. Media whose memory is still
+// in the trash cannot be restored on its own.
+func (r *MediaRepo) RestoreMedia(ctx context.Context, id string) error {
+	ctx = withSession(ctx, r.session)
+	var doc mediaDoc
+	err := r.collection().FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}).Decode(&doc)
+	if err != nil {
+		return err
+	}
+
+	var parent memoryDoc
+	if err := r.db.Collection(memoriesCollection).FindOne(ctx, bson.M{"_id": doc.MemoryID}).Decode(&parent); err != nil {
+		return err
+	}
+	if parent.DeletedAt != nil {
+		return storage.Errorf(storage.ErrConflict, "cannot restore media %s: its memory is in the trash", id)
+	}
+
+	result, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{"$set": bson.M{"deleted_at": nil}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// PurgeMedia permanently deletes media that were trashed before the given time.
+func (r *MediaRepo) PurgeMedia(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withSession(ctx, r.session)
+	result, err := r.collection().DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$ne": nil, "$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,952 @@
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultSearchLanguage is the text search language used for memories and
+// search terms that do not specify one.
+const defaultSearchLanguage = "english"
+
+// memoryDoc is the document stored in the memories collection.
+type memoryDoc struct {
+	ID          string     `bson:"_id"`
+	UserID      string     `bson:"user_id"`
+	Title       string     `bson:"title"`
+	Description string     `bson:"description"`
+	Date        time.Time  `bson:"date"`
+	Tags        []string   `bson:"tags"`
+	Latitude    *float64   `bson:"latitude"`
+	Longitude   *float64   `bson:"longitude"`
+	PlaceName   string     `bson:"place_name"`
+	Privacy     string     `bson:"privacy"`
+	Language    string     `bson:"language"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at"`
+	DeletedAt   *time.Time `bson:"deleted_at"`
+	Version     int64      `bson:"version"`
+}
+
+func (d *memoryDoc) toProto() *memory.Memory {
+	return &memory.Memory{
+		Id:          d.ID,
+		UserId:      d.UserID,
+		Title:       d.Title,
+		Description: d.Description,
+		Date:        formatTime(&d.Date),
+		Tags:        d.Tags,
+		Latitude:    d.Latitude,
+		Longitude:   d.Longitude,
+		PlaceName:   d.PlaceName,
+		Privacy:     d.Privacy,
+		Language:    d.Language,
+		CreatedAt:   formatTime(&d.CreatedAt),
+		UpdatedAt:   formatTime(&d.UpdatedAt),
+		DeletedAt:   formatTime(d.DeletedAt),
+		Version:     d.Version,
+	}
+}
+
+// memoryResult is a memory returned by GetAllMemories together with its
+// computed ranking columns.
+type memoryResult struct {
+	memoryDoc      `bson:",inline"`
+	SearchRank     float64 `bson:"search_rank"`
+	DistanceMeters float64 `bson:"distance_meters"`
+}
+
+// revisionDoc is the document stored in the memory_revisions collection.
+// Old and new values are JSON encoded.
+type revisionDoc struct {
+	ID            string            `bson:"_id"`
+	MemoryID      string            `bson:"memory_id"`
+	Version       int64             `bson:"version"`
+	ChangedBy     string            `bson:"changed_by"`
+	ChangedFields []string          `bson:"changed_fields"`
+	OldValues     map[string]string `bson:"old_values"`
+	NewValues     map[string]string `bson:"new_values"`
+	Snapshot      memoryDoc         `bson:"snapshot"`
+	CreatedAt     time.Time         `bson:"created_at"`
+}
+
+type MemoryRepo struct {
+	db      *mongo.Database
+	session mongo.Session // set on repositories bound to a transaction
+}
+
+func NewMemoryRepo(db *mongo.Database) *MemoryRepo {
+	return &MemoryRepo{
+		db: db,
+	}
+}
+
+func (r *MemoryRepo) collection() *mongo.Collection {
+	return r.db.Collection(memoriesCollection)
+}
+
+func (r *MemoryRepo) CreateMemory(ctx context.Context, memory *models.CreateMemoryModel) (string, error) {
+	ctx = withSession(ctx, r.session)
+	doc := newMemoryDoc(memory)
+
+	if _, err := r.collection().InsertOne(ctx, doc); err != nil {
+		return "", err
+	}
+
+	if err := r.recordRevision(ctx, nil, doc, ""); err != nil {
+		return "", err
+	}
+
+	return memory.ID, nil
+}
+
+// BulkCreateMemories inserts the memories and their first revisions with
+// one write each, in a transaction.
+func (r *MemoryRepo) BulkCreateMemories(ctx context.Context, memories []*models.CreateMemoryModel) ([]string, error) {
+	ids := make([]string, len(memories))
+	docs := make([]interface{}, len(memories))
+	revisions := make([]interface{}, len(memories))
+	for i, memory := range memories {
+		doc := newMemoryDoc(memory)
+		ids[i] = doc.ID
+		docs[i] = doc
+		revisions[i] = newRevision(nil, doc, "")
+	}
+	if len(docs) == 0 {
+		return ids, nil
+	}
+
+	err := inTx(ctx, r.db, r.session, func(ctx context.Context) error {
+		if _, err := r.collection().InsertMany(ctx, docs); err != nil {
+			return err
+		}
+		_, err := r.db.Collection(revisionsCollection).InsertMany(ctx, revisions)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// newMemoryDoc returns the document of a new memory, giving it an id if it
+// has none.
+func newMemoryDoc(memory *models.CreateMemoryModel) *memoryDoc {
+	if memory.ID == "" {
+		memory.ID = uuid.NewString()
+	}
+	language := memory.Language
+	if language == "" {
+		language = defaultSearchLanguage
+	}
+
+	ts := now()
+	return &memoryDoc{
+		ID:          memory.ID,
+		UserID:      memory.UserID,
+		Title:       memory.Title,
+		Description: memory.Description,
+		Date:        memory.Date,
+		Tags:        memory.Tags,
+		Latitude:    memory.Latitude,
+		Longitude:   memory.Longitude,
+		PlaceName:   memory.PlaceName,
+		Privacy:     memory.Privacy,
+		Language:    language,
+		CreatedAt:   ts,
+		UpdatedAt:   ts,
+		Version:     1,
+	}
+}
+
+func (r *MemoryRepo) GetMemoryByID(ctx context.Context, id string) (*memory.Memory, error) {
+	ctx = withSession(ctx, r.session)
+	var doc memoryDoc
+	err := r.collection().FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.toProto(), nil
+}
+
+func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	ctx = withSession(ctx, r.session)
+	cond, err := memoryFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relevance and distance orderings are not keyset-friendly, so they are
+	// paginated with page/limit only.
+	sortBy := bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}}
+	keyset := true
+	switch req.SortBy {
+	case "", "date":
+	case "relevance":
+		if !cond.search {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "sort by relevance requires a search term")
+		}
+		sortBy = bson.D{{Key: "search_rank", Value: -1}, {Key: "date", Value: -1}, {Key: "_id", Value: -1}}
+		keyset = false
+	case "distance":
+		if cond.center == nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "sort by distance requires a center point")
+		}
+		sortBy = bson.D{{Key: "distance_meters", Value: 1}, {Key: "_id", Value: -1}}
+		keyset = false
+	default:
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid sort_by %q", req.SortBy)
+	}
+	if !keyset && req.PageToken != "" {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "page tokens are only supported when sorting by date")
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: cond.filter}}}
+	if cond.search {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"search_rank": bson.M{"$meta": "textScore"}}}})
+	}
+	if cond.center != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"distance_meters": distanceExpr(cond.center[0], cond.center[1])}}})
+		if cond.radius > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"distance_meters": bson.M{"$lte": cond.radius}}}})
+		}
+	}
+
+	total, err := countPipeline(ctx, r.collection(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last document of the
+		// previous page, so documents inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"date": bson.M{"$lt": cursor.Time}},
+			bson.M{"date": cursor.Time, "_id": bson.M{"$lt": cursor.ID}},
+		}}}})
+		offset = 0
+	}
+
+	// One extra document tells us whether there is a next page.
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: sortBy}},
+		bson.D{{Key: "$skip", Value: int64(offset)}},
+		bson.D{{Key: "$limit", Value: int64(limit) + 1}},
+	)
+
+	cur, err := r.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var (
+		memories      []*memory.Memory
+		lastDate      time.Time
+		nextPageToken string
+	)
+
+	for cur.Next(ctx) {
+		var doc memoryResult
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		if int32(len(memories)) == limit {
+			if keyset {
+				nextPageToken = helper.EncodeCursor(lastDate, memories[len(memories)-1].Id)
+			}
+			break
+		}
+		memoryModel := doc.toProto()
+		memoryModel.SearchRank = doc.SearchRank
+		memoryModel.DistanceMeters = doc.DistanceMeters
+		memories = append(memories, memoryModel)
+		lastDate = doc.Date
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMemoriesResponse{
+		Memories:      memories,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// countPipeline counts the documents produced by pipeline.
+func countPipeline(ctx context.Context, coll *mongo.Collection, pipeline mongo.Pipeline) (int32, error) {
+	counting := append(mongo.Pipeline{}, pipeline...)
+	counting = append(counting, bson.D{{Key: "$count", Value: "count"}})
+
+	cur, err := coll.Aggregate(ctx, counting)
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(ctx)
+
+	var result struct {
+		Count int32 `bson:"count"`
+	}
+	if cur.Next(ctx) {
+		if err := cur.Decode(&result); err != nil {
+			return 0, err
+		}
+	}
+	return result.Count, cur.Err()
+}
+
+// memoryConditions holds the match stage of a GetAllMemories request along
+// with what is needed to rank and measure the matches.
+type memoryConditions struct {
+	filter bson.M
+	search bool        // set when searching, enables the text score
+	center *[2]float64 // latitude/longitude of the requested center point
+	radius float64     // maximum distance from center, 0 for none
+}
+
+// memoryFilter builds the match stage shared by the list and count
+// pipelines of GetAllMemories. It mirrors the Postgres filter semantics.
+func memoryFilter(req *memory.GetAllMemoriesRequest) (*memoryConditions, error) {
+	filter := bson.M{"deleted_at": nil}
+	var and bson.A
+	cond := &memoryConditions{}
+
+	if req.SearchTerm != "" {
+		// $text must sit at the top level of the first match stage.
+		filter["$text"] = bson.M{"$search": req.SearchTerm, "$language": searchLanguage(req.SearchLanguage)}
+		cond.search = true
+	}
+
+	if req.UserId != "" {
+		and = append(and, bson.M{"user_id": req.UserId})
+	}
+
+	if req.Title != "" {
+		and = append(and, bson.M{"title": containsPattern(req.Title)})
+	}
+
+	if req.Description != "" {
+		and = append(and, bson.M{"description": containsPattern(req.Description)})
+	}
+
+	if len(req.Tags) > 0 {
+		and = append(and, bson.M{"tags": bson.M{"$in": req.Tags}}) // any of the tags
+	}
+
+	if req.StartDate != "" {
+		startTime, err := time.Parse(time.RFC3339, req.StartDate)
+		if err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid start time format: %w", err)
+		}
+		and = append(and, bson.M{"date": bson.M{"$gte": startTime}})
+	}
+
+	if req.EndDate != "" {
+		endTime, err := time.Parse(time.RFC3339, req.EndDate)
+		if err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid end time format: %w", err)
+		}
+		and = append(and, bson.M{"date": bson.M{"$lte": endTime}})
+	}
+
+	if req.Latitude != nil {
+		and = append(and, bson.M{"latitude": *req.Latitude})
+	}
+
+	if req.Longitude != nil {
+		and = append(and, bson.M{"longitude": *req.Longitude})
+	}
+
+	if (req.CenterLatitude == nil) != (req.CenterLongitude == nil) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "center_latitude and center_longitude must be set together")
+	}
+	if req.RadiusMeters < 0 {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "radius_meters must not be negative")
+	}
+	if req.RadiusMeters > 0 && req.CenterLatitude == nil {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "radius_meters requires center_latitude and center_longitude")
+	}
+	if req.CenterLatitude != nil {
+		lat, lon := *req.CenterLatitude, *req.CenterLongitude
+		if err := helper.ValidateCoordinates(lat, lon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid center point: %w", err)
+		}
+
+		cond.center = &[2]float64{lat, lon}
+
+		and = append(and, bson.M{"latitude": bson.M{"$ne": nil}}, bson.M{"longitude": bson.M{"$ne": nil}})
+		if req.RadiusMeters > 0 {
+			// Cheap bounding-box pre-filter (index friendly) before the exact distance check.
+			minLat, minLon, maxLat, maxLon, ok := helper.BoundingBox(lat, lon, req.RadiusMeters)
+			and = append(and, bson.M{"latitude": bson.M{"$gte": minLat, "$lte": maxLat}})
+			if ok {
+				and = append(and, longitudeRange(minLon, maxLon))
+			}
+			cond.radius = req.RadiusMeters
+		}
+	}
+
+	bbox := []*float64{req.MinLatitude, req.MinLongitude, req.MaxLatitude, req.MaxLongitude}
+	bboxSet := 0
+	for _, v := range bbox {
+		if v != nil {
+			bboxSet++
+		}
+	}
+	if bboxSet != 0 && bboxSet != len(bbox) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "bounding box requires min/max latitude and longitude")
+	}
+	if bboxSet == len(bbox) {
+		minLat, minLon, maxLat, maxLon := *req.MinLatitude, *req.MinLongitude, *req.MaxLatitude, *req.MaxLongitude
+		if err := helper.ValidateCoordinates(minLat, minLon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: %w", err)
+		}
+		if err := helper.ValidateCoordinates(maxLat, maxLon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: %w", err)
+		}
+		if minLat > maxLat {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: min_latitude is greater than max_latitude")
+		}
+
+		and = append(and, bson.M{"latitude": bson.M{"$gte": minLat, "$lte": maxLat}}, longitudeRange(minLon, maxLon))
+	}
+
+	if req.PlaceName != "" {
+		and = append(and, bson.M{"place_name": containsPattern(req.PlaceName)})
+	}
+
+	if req.Privacy != "" {
+		and = append(and, bson.M{"privacy": req.Privacy})
+	}
+
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+	cond.filter = filter
+	return cond, nil
+}
+
+// searchLanguage maps a Postgres text search configuration name to the
+// MongoDB text search language.
+func searchLanguage(language string) string {
+	switch language {
+	case "":
+		return defaultSearchLanguage
+	case "simple":
+		return "none"
+	}
+	return language
+}
+
+// distanceExpr returns an aggregation expression for the haversine distance
+// in meters between a memory and the given point, the same formula the
+// Postgres repository uses.
+func distanceExpr(lat, lon float64) bson.M {
+	sinHalf := func(field string, v float64) bson.M {
+		return bson.M{"$sin": bson.M{"$divide": bson.A{
+			bson.M{"$degreesToRadians": bson.M{"$subtract": bson.A{"$" + field, v}}}, 2,
+		}}}
+	}
+	a := bson.M{"$add": bson.A{
+		bson.M{"$pow": bson.A{sinHalf("latitude", lat), 2}},
+		bson.M{"$multiply": bson.A{
+			math.Cos(lat * math.Pi / 180),
+			bson.M{"$cos": bson.M{"$degreesToRadians": "$latitude"}},
+			bson.M{"$pow": bson.A{sinHalf("longitude", lon), 2}},
+		}},
+	}}
+	return bson.M{"$multiply": bson.A{
+		2 * helper.EarthRadiusMeters,
+		bson.M{"$asin": bson.M{"$min": bson.A{1, bson.M{"$sqrt": a}}}},
+	}}
+}
+
+// longitudeRange returns the longitude condition for a box edge pair. A west
+// edge greater than the east edge means the range crosses the antimeridian.
+func longitudeRange(minLon, maxLon float64) bson.M {
+	if minLon <= maxLon {
+		return bson.M{"longitude": bson.M{"$gte": minLon, "$lte": maxLon}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"longitude": bson.M{"$gte": minLon}},
+		bson.M{"longitude": bson.M{"$lte": maxLon}},
+	}}
+}
+
+func (r *MemoryRepo) UpdateMemory(ctx context.Context, memory *models.UpdateMemoryModel) error {
+	ctx = withSession(ctx, r.session)
+	set := bson.M{
+		"user_id":     memory.UserID,
+		"title":       memory.Title,
+		"description": memory.Description,
+		"date":        memory.Date,
+		"tags":        memory.Tags,
+		"latitude":    memory.Latitude,
+		"longitude":   memory.Longitude,
+		"place_name":  memory.PlaceName,
+		"privacy":     memory.Privacy,
+	}
+	if memory.Language != "" {
+		set["language"] = memory.Language
+	}
+
+	filter := versionFilter(bson.M{"_id": memory.ID, "deleted_at": nil}, memory.ExpectedVersion)
+	_, _, err := r.update(ctx, filter, set, memory.ChangedBy)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return missingOrConflict(ctx, r.db, memoriesCollection, memory.ID, memory.ExpectedVersion)
+	}
+	return err
+}
+
+func (r *MemoryRepo) PatchMemory(ctx context.Context, memory *models.PatchMemoryModel) error {
+	ctx = withSession(ctx, r.session)
+	set := bson.M{}
+
+	if memory.Title != nil {
+		set["title"] = *memory.Title
+	}
+
+	if memory.Description != nil {
+		set["description"] = *memory.Description
+	}
+
+	if memory.Date != nil {
+		set["date"] = *memory.Date
+	}
+
+	if memory.Tags != nil {
+		set["tags"] = *memory.Tags
+	}
+
+	if memory.Latitude != nil {
+		set["latitude"] = *memory.Latitude
+	}
+
+	if memory.Longitude != nil {
+		set["longitude"] = *memory.Longitude
+	}
+
+	if memory.PlaceName != nil {
+		set["place_name"] = *memory.PlaceName
+	}
+
+	if memory.Privacy != nil {
+		set["privacy"] = *memory.Privacy
+	}
+
+	if memory.Language != nil {
+		set["language"] = *memory.Language
+	}
+
+	if len(set) == 0 {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	filter := versionFilter(bson.M{"_id": memory.ID, "deleted_at": nil}, memory.ExpectedVersion)
+	_, _, err := r.update(ctx, filter, set, memory.ChangedBy)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return missingOrConflict(ctx, r.db, memoriesCollection, memory.ID, memory.ExpectedVersion)
+	}
+	return err
+}
+
+// update applies set to the memory matched by filter, bumps its version and
+// records the change as a revision. It returns the memory before and after
+// the change, or mongo.ErrNoDocuments if nothing matched.
+func (r *MemoryRepo) update(ctx context.Context, filter, set bson.M, changedBy string) (*memoryDoc, *memoryDoc, error) {
+	if _, ok := set["updated_at"]; !ok {
+		set["updated_at"] = now()
+	}
+
+	var old memoryDoc
+	err := r.collection().FindOneAndUpdate(ctx, filter, bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	}).Decode(&old)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Rebuild the new state from the old one instead of reading it back, so a
+	// concurrent write cannot end up in this revision.
+	updated, err := applySet(&old, set)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.recordRevision(ctx, &old, updated, changedBy); err != nil {
+		return nil, nil, err
+	}
+	return &old, updated, nil
+}
+
+// applySet returns a copy of doc with the fields of set applied and its
+// version bumped.
+func applySet(doc *memoryDoc, set bson.M) (*memoryDoc, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range set {
+		fields[k] = v
+	}
+	fields["version"] = doc.Version + 1
+
+	if raw, err = bson.Marshal(fields); err != nil {
+		return nil, err
+	}
+	var updated memoryDoc
+	if err := bson.Unmarshal(raw, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteMemory moves a memory to the trash together with its media and
+// comments and reports how many children went with it. All of them share the
+// same deleted_at, so RestoreMemory can bring back exactly the children that
+// were trashed with the memory. The cascade runs in a transaction.
+func (r *MemoryRepo) DeleteMemory(ctx context.Context, id string) (*memory.DeleteMemoryResponse, error) {
+	var media, comments *mongo.UpdateResult
+	err := inTx(ctx, r.db, r.session, func(ctx context.Context) error {
+		ts := now()
+		_, _, err := r.update(ctx, bson.M{"_id": id, "deleted_at": nil}, bson.M{"deleted_at": ts, "updated_at": ts}, "")
+		if err != nil {
+			return err
+		}
+
+		media, err = r.db.Collection(mediaCollection).UpdateMany(ctx,
+			bson.M{"memory_id": id, "deleted_at": nil},
+			bson.M{"$set": bson.M{"deleted_at": ts}},
+		)
+		if err != nil {
+			return err
+		}
+
+		comments, err = r.db.Collection(commentsCollection).UpdateMany(ctx,
+			bson.M{"memory_id": id, "deleted_at": nil},
+			bson.M{"$set": bson.M{"deleted_at": ts, "updated_at": ts}, "$inc": bson.M{"version": 1}},
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &memory.DeleteMemoryResponse{
+		Success:         true,
+		DeletedMedia:    int32(media.ModifiedCount),
+		DeletedComments: int32(comments.ModifiedCount),
+	}, nil
+}
+
+func (r *MemoryRepo) GetDeletedMemories(ctx context.Context, req *memory.GetDeletedMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	ctx = withSession(ctx, r.session)
+	filter := bson.M{"deleted_at": bson.M{"$ne": nil}}
+	if req.UserId != "" {
+		filter["user_id"] = req.UserId
+	}
+
+	total, err := r.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cur, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var memories []*memory.Memory
+	for cur.Next(ctx) {
+		var doc memoryDoc
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		memories = append(memories, doc.toProto())
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMemoriesResponse{
+		Memories: memories,
+		Count:    int32(total),
+	}, nil
+}
+
+// RestoreMemory takes a memory out of the trash along with the media and
+// comments that were trashed together with it. The cascade runs in a
+// transaction.
+func (r *MemoryRepo) RestoreMemory(ctx context.Context, id string) error {
+	return inTx(ctx, r.db, r.session, func(ctx context.Context) error {
+		old, _, err := r.update(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}, bson.M{"deleted_at": nil}, "")
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.db.Collection(mediaCollection).UpdateMany(ctx,
+			bson.M{"memory_id": id, "deleted_at": old.DeletedAt},
+			bson.M{"$set": bson.M{"deleted_at": nil}},
+		); err != nil {
+			return err
+		}
+
+		_, err = r.db.Collection(commentsCollection).UpdateMany(ctx,
+			bson.M{"memory_id": id, "deleted_at": old.DeletedAt},
+			bson.M{"$set": bson.M{"deleted_at": nil, "updated_at": now()}, "$inc": bson.M{"version": 1}},
+		)
+		return err
+	})
+}
+
+// PurgeMemories permanently deletes memories that were trashed before the
+// given time together with their media, comments and revisions.
+func (r *MemoryRepo) PurgeMemories(ctx context.Context, before time.Time) (int64, error) {
+	ctx = withSession(ctx, r.session)
+	ids, err := r.collection().Distinct(ctx, "_id", bson.M{"deleted_at": bson.M{"$ne": nil, "$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := r.collection().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, collection := range []string{mediaCollection, commentsCollection, revisionsCollection} {
+		if _, err := r.db.Collection(collection).DeleteMany(ctx, bson.M{"memory_id": bson.M{"$in": ids}}); err != nil {
+			return 0, err
+		}
+	}
+
+	return result.DeletedCount, nil
+}
+
+// recordRevision stores the change from old to updated as a revision. A nil
+// old records the creation of the memory. Changes that touch no tracked
+// field are not recorded. The editor defaults to the memory owner.
+func (r *MemoryRepo) recordRevision(ctx context.Context, old, updated *memoryDoc, changedBy string) error {
+	revision := newRevision(old, updated, changedBy)
+	if revision == nil {
+		return nil
+	}
+
+	_, err := r.db.Collection(revisionsCollection).InsertOne(ctx, revision)
+	return err
+}
+
+// newRevision returns the revision recordRevision stores, or nil if there is
+// nothing to record.
+func newRevision(old, updated *memoryDoc, changedBy string) *revisionDoc {
+	newFields := revisionFields(updated)
+	var oldFields map[string]string
+	if old != nil {
+		oldFields = revisionFields(old)
+	}
+
+	var changed []string
+	for field, value := range newFields {
+		if old == nil || oldFields[field] != value {
+			changed = append(changed, field)
+		}
+	}
+	if old != nil && len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+
+	oldValues := make(map[string]string)
+	newValues := make(map[string]string)
+	for _, field := range changed {
+		if old != nil {
+			oldValues[field] = oldFields[field]
+		}
+		newValues[field] = newFields[field]
+	}
+
+	if changedBy == "" {
+		changedBy = updated.UserID
+	}
+
+	return &revisionDoc{
+		ID:            uuid.NewString(),
+		MemoryID:      updated.ID,
+		Version:       updated.Version,
+		ChangedBy:     changedBy,
+		ChangedFields: changed,
+		OldValues:     oldValues,
+		NewValues:     newValues,
+		Snapshot:      *updated,
+		CreatedAt:     updated.UpdatedAt,
+	}
+}
+
+// revisionFields returns the JSON encoded fields of a memory that revisions
+// track. Bookkeeping fields are left out.
+func revisionFields(doc *memoryDoc) map[string]string {
+	fields := map[string]interface{}{
+		"user_id":     doc.UserID,
+		"title":       doc.Title,
+		"description": doc.Description,
+		"date":        doc.Date,
+		"tags":        doc.Tags,
+		"latitude":    doc.Latitude,
+		"longitude":   doc.Longitude,
+		"place_name":  doc.PlaceName,
+		"privacy":     doc.Privacy,
+		"language":    doc.Language,
+		"created_at":  doc.CreatedAt,
+		"deleted_at":  doc.DeletedAt,
+	}
+
+	encoded := make(map[string]string, len(fields))
+	for field, value := range fields {
+		b, _ := json.Marshal(value)
+		encoded[field] = string(b)
+	}
+	return encoded
+}
+
+// ListMemoryRevisions lists the recorded changes of a memory, newest first.
+func (r *MemoryRepo) ListMemoryRevisions(ctx context.Context, req *memory.ListMemoryRevisionsRequest) (*memory.ListMemoryRevisionsResponse, error) {
+	ctx = withSession(ctx, r.session)
+	filter := bson.M{"memory_id": req.MemoryId}
+	revisions := r.db.Collection(revisionsCollection)
+
+	total, err := revisions.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "version", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cur, err := revisions.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var list []*memory.MemoryRevision
+	for cur.Next(ctx) {
+		var doc revisionDoc
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		revision := &memory.MemoryRevision{
+			Id:        doc.ID,
+			MemoryId:  doc.MemoryID,
+			Version:   doc.Version,
+			ChangedBy: doc.ChangedBy,
+			CreatedAt: formatTime(&doc.CreatedAt),
+		}
+		for _, field := range doc.ChangedFields {
+			revision.Changes = append(revision.Changes, &memory.FieldChange{
+				Field:    field,
+				OldValue: doc.OldValues[field],
+				NewValue: doc.NewValues[field],
+			})
+		}
+		list = append(list, revision)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.ListMemoryRevisionsResponse{
+		Revisions: list,
+		Count:     int32(total),
+	}, nil
+}
+
+// GetMemoryAsOf returns the memory as it was at the given time, rebuilt from
+// the latest revision recorded at or before it.
+func (r *MemoryRepo) GetMemoryAsOf(ctx context.Context, id string, asOf time.Time) (*memory.Memory, error) {
+	ctx = withSession(ctx, r.session)
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "version", Value: -1}})
+
+	var doc revisionDoc
+	err := r.db.Collection(revisionsCollection).FindOne(ctx, bson.M{
+		"memory_id":  id,
+		"created_at": bson.M{"$lte": asOf},
+	}, opts).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Snapshot.toProto(), nil
+}
+
+// RestoreMemoryRevision writes the content of an earlier revision back to
+// the memory. The restore is itself recorded as a new revision, so nothing
+// in the history is lost.
+func (r *MemoryRepo) RestoreMemoryRevision(ctx context.Context, req *memory.RestoreMemoryRevisionRequest) error {
+	ctx = withSession(ctx, r.session)
+	var revision revisionDoc
+	err := r.db.Collection(revisionsCollection).FindOne(ctx, bson.M{
+		"_id":       req.RevisionId,
+		"memory_id": req.MemoryId,
+	}).Decode(&revision)
+	if err != nil {
+		return err
+	}
+
+	snapshot := revision.Snapshot
+	set := bson.M{
+		"title":       snapshot.Title,
+		"description": snapshot.Description,
+		"date":        snapshot.Date,
+		"tags":        snapshot.Tags,
+		"latitude":    snapshot.Latitude,
+		"longitude":   snapshot.Longitude,
+		"place_name":  snapshot.PlaceName,
+		"privacy":     snapshot.Privacy,
+		"language":    snapshot.Language,
+	}
+
+	filter := versionFilter(bson.M{"_id": req.MemoryId, "deleted_at": nil}, req.ExpectedVersion)
+	_, _, err = r.update(ctx, filter, set, req.ChangedBy)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return missingOrConflict(ctx, r.db, memoriesCollection, req.MemoryId, req.ExpectedVersion)
+	}
+	return err
+}
@@ -0,0 +1,273 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/time_capsule/memory-service/config"
+	"github.com/time_capsule/memory-service/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection names.
+const (
+	memoriesCollection  = "memories"
+	mediaCollection     = "media"
+	commentsCollection  = "comments"
+	revisionsCollection = "memory_revisions"
+	commandsCollection  = "processed_commands"
+)
+
+// Storage implements the storage.StorageI interface for MongoDB.
+type Storage struct {
+	db       *mongo.Database
+	session  mongo.Session
+	MemoryS  storage.MemoryI
+	MediaS   storage.MediaI
+	CommentS storage.CommentI
+	CommandS storage.CommandI
+}
+
+// NewMongoStorage creates a new MongoDB storage instance.
+func NewMongoStorage(cfg config.Config) (storage.StorageI, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.MongoConnectTimeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		slog.Warn("Unable to connect to MongoDB", "err", err)
+		return nil, err
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		slog.Warn("Unable to ping MongoDB", "err", err)
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+
+	db := client.Database(cfg.MongoDB)
+	if err := EnsureIndexes(ctx, db); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	return NewStorage(db), nil
+}
+
+// NewStorage creates a MongoDB storage instance on top of a database.
+func NewStorage(db *mongo.Database) *Storage {
+	return newStorage(db, nil)
+}
+
+// newStorage creates a storage instance whose repositories run in session,
+// if one is given.
+func newStorage(db *mongo.Database, session mongo.Session) *Storage {
+	return &Storage{
+		db:       db,
+		session:  session,
+		MemoryS:  classifier.Memory(&MemoryRepo{db: db, session: session}),
+		MediaS:   classifier.Media(&MediaRepo{db: db, session: session}),
+		CommentS: classifier.Comment(&CommentRepo{db: db, session: session}),
+		CommandS: classifier.Command(&CommandRepo{db: db, session: session}),
+	}
+}
+
+// WithTx runs fn with repositories bound to a single multi-document
+// transaction, which is committed if fn returns nil and aborted if it fails
+// or panics. Transactions need a replica set or sharded cluster. MongoDB
+// has no nested transactions, so calling WithTx on a transactional storage
+// runs fn in the enclosing transaction.
+func (s *Storage) WithTx(ctx context.Context, fn func(tx storage.StorageI) error) error {
+	if s.session != nil {
+		return fn(s)
+	}
+
+	session, err := s.db.Client().StartSession()
+	if err != nil {
+		return classifier.Classify(err)
+	}
+	// Ending the session aborts the transaction unless it was committed,
+	// which covers both a failing and a panicking fn.
+	defer session.EndSession(context.Background())
+
+	if err := session.StartTransaction(); err != nil {
+		return classifier.Classify(err)
+	}
+	if err := fn(newStorage(s.db, session)); err != nil {
+		return err
+	}
+	return classifier.Classify(session.CommitTransaction(ctx))
+}
+
+// inTx runs fn in the transaction of session, or in a transaction of its own
+// if session is nil, so that writes spanning several collections are atomic.
+// fn runs its operations with the context it is passed and may be retried on
+// transient transaction errors.
+func inTx(ctx context.Context, db *mongo.Database, session mongo.Session, fn func(ctx context.Context) error) error {
+	if session != nil {
+		return fn(mongo.NewSessionContext(ctx, session))
+	}
+
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.Background())
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// withSession returns ctx bound to session, so operations run in its
+// transaction. A nil session leaves ctx unchanged.
+func withSession(ctx context.Context, session mongo.Session) context.Context {
+	if session == nil {
+		return ctx
+	}
+	return mongo.NewSessionContext(ctx, session)
+}
+
+// EnsureIndexes creates the indexes the repositories rely on. It is
+// idempotent and runs on every startup.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := map[string][]mongo.IndexModel{
+		memoriesCollection: {
+			{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			{Keys: bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "tags", Value: 1}}},
+			{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+			{
+				// Same weighting as the Postgres search vector: title, then
+				// tags, description and place name. Documents keep their
+				// language in a field Mongo does not know about, so the
+				// language is chosen per query instead.
+				Keys: bson.D{
+					{Key: "title", Value: "text"},
+					{Key: "tags", Value: "text"},
+					{Key: "description", Value: "text"},
+					{Key: "place_name", Value: "text"},
+				},
+				Options: options.Index().
+					SetWeights(bson.D{
+						{Key: "title", Value: 8},
+						{Key: "tags", Value: 4},
+						{Key: "description", Value: 2},
+						{Key: "place_name", Value: 1},
+					}).
+					SetDefaultLanguage(defaultSearchLanguage).
+					SetLanguageOverride("text_language"),
+			},
+		},
+		mediaCollection: {
+			{Keys: bson.D{{Key: "memory_id", Value: 1}}},
+			{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		commentsCollection: {
+			{Keys: bson.D{{Key: "memory_id", Value: 1}}},
+			{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		},
+		revisionsCollection: {
+			{Keys: bson.D{{Key: "memory_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		},
+		commandsCollection: {
+			{Keys: bson.D{{Key: "processed_at", Value: 1}}},
+		},
+	}
+
+	for collection, models := range indexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return fmt.Errorf("%s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+// Memory returns the MemoryI implementation for MongoDB.
+func (s *Storage) Memory() storage.MemoryI {
+	return s.MemoryS
+}
+
+// Media returns the MediaI implementation for MongoDB.
+func (s *Storage) Media() storage.MediaI {
+	return s.MediaS
+}
+
+// Comment returns the CommentI implementation for MongoDB.
+func (s *Storage) Comment() storage.CommentI {
+	return s.CommentS
+}
+
+// Command returns the CommandI implementation for MongoDB.
+func (s *Storage) Command() storage.CommandI {
+	return s.CommandS
+}
+
+// ensureMemoryExists returns storage.ErrMemoryNotFound unless the memory
+// exists and is not in the trash.
+func ensureMemoryExists(ctx context.Context, db *mongo.Database, id string) error {
+	n, err := db.Collection(memoriesCollection).CountDocuments(ctx, bson.M{"_id": id, "deleted_at": nil})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, id)
+	}
+	return nil
+}
+
+// missingOrConflict explains why a versioned write to collection matched no
+// documents: either the document is gone (mongo.ErrNoDocuments) or it has
+// moved past the expected version (storage.ErrVersionConflict).
+func missingOrConflict(ctx context.Context, db *mongo.Database, collection, id string, expectedVersion int64) error {
+	if expectedVersion == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	var doc struct {
+		Version int64 `bson:"version"`
+	}
+	err := db.Collection(collection).FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&doc)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s %s is at version %d, expected %d", storage.ErrVersionConflict, collection, id, doc.Version, expectedVersion)
+}
+
+// versionFilter narrows filter to documents at the expected version. Zero
+// skips the check.
+func versionFilter(filter bson.M, expectedVersion int64) bson.M {
+	if expectedVersion != 0 {
+		filter["version"] = expectedVersion
+	}
+	return filter
+}
+
+// now returns the current time at the millisecond precision MongoDB stores,
+// so values read back compare equal to the ones written.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// formatTime formats a stored time like helper.DateToString does for SQL
+// NULL-able columns: the zero or nil time becomes an empty string.
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// containsPattern returns a case-insensitive regular expression matching
+// values that contain s, like ILIKE '%s%' in Postgres.
+func containsPattern(s string) bson.M {
+	return bson.M{"$regex": regexp.QuoteMeta(s), "$options": "i"}
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/time_capsule/memory-service/storage"
+)
+
+type CommandRepo struct {
+	db querier
+}
+
+func NewCommandRepo(db querier) *CommandRepo {
+	return &CommandRepo{
+		db: db,
+	}
+}
+
+// MarkCommandProcessed records a processed command. A command that was
+// recorded before is skipped rather than violating the primary key, which
+// would abort the surrounding transaction.
+func (r *CommandRepo) MarkCommandProcessed(ctx context.Context, id, operation string) error {
+	result, err := r.db.Exec(ctx, `
+		INSERT INTO processed_commands (id, operation) VALUES ($1, $2)
+		ON CONFLICT (id) DO NOTHING
+	`, id, operation)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return storage.Errorf(storage.ErrAlreadyExists, "command %s already processed", id)
+	}
+	return nil
+}
+
+// PurgeCommands forgets the commands processed before the given time.
+func (r *CommandRepo) PurgeCommands(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM processed_commands
+		WHERE processed_at < $1
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
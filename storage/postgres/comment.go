@@ -3,20 +3,24 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/time_capsule/memory-service/genproto/memory"
 	"github.com/time_capsule/memory-service/helper"
 	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
 )
 
 type CommentRepo struct {
-	db *pgx.Conn
+	db querier
 }
 
-func NewCommentRepo(db *pgx.Conn) *CommentRepo {
+func NewCommentRepo(db querier) *CommentRepo {
 	return &CommentRepo{
 		db: db,
 	}
@@ -28,14 +32,18 @@ func (r *CommentRepo) CreateComment(ctx context.Context, comment *models.CreateC
 	}
 	query := `
 		INSERT INTO comments (
-			id, 
+			id,
 			memory_id,
 			user_id,
 			content,
-			created_at
-		) VALUES (
-			$1, $2, $3, $4, NOW()
-		) RETURNING id
+			created_at,
+			updated_at
+		)
+		SELECT $1, $2, $3, $4, NOW(), NOW()
+		WHERE EXISTS (
+			SELECT 1 FROM memories WHERE id = $2 AND deleted_at IS NULL
+		)
+		RETURNING id
 	`
 
 	err := r.db.QueryRow(ctx, query,
@@ -46,16 +54,74 @@ func (r *CommentRepo) CreateComment(ctx context.Context, comment *models.CreateC
 	).Scan(&comment.ID)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, comment.MemoryID)
+		}
 		return "", err
 	}
 
 	return comment.ID, nil
 }
 
+// BulkCreateComments inserts the comments with multi-row statements in one
+// transaction. Like CreateComment it refuses comments on missing or trashed
+// memories.
+func (r *CommentRepo) BulkCreateComments(ctx context.Context, comments []*models.CreateCommentModel) ([]string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ids := make([]string, 0, len(comments))
+	for start := 0; start < len(comments); start += bulkRows {
+		chunk := comments[start:min(start+bulkRows, len(comments))]
+		rows := make([]string, len(chunk))
+		memoryIDs := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*4)
+		for i, comment := range chunk {
+			if comment.ID == "" {
+				comment.ID = uuid.NewString()
+			}
+			rows[i] = fmt.Sprintf(`($%d::uuid, $%d::uuid, $%d::uuid, $%d)`, rowParams(i, 4)...)
+			memoryIDs[i] = comment.MemoryID
+			args = append(args, comment.ID, comment.MemoryID, comment.UserID, comment.Content)
+			ids = append(ids, comment.ID)
+		}
+
+		query := `
+			INSERT INTO comments (
+				id,
+				memory_id,
+				user_id,
+				content,
+				created_at,
+				updated_at
+			)
+			SELECT v.id, v.memory_id, v.user_id, v.content, NOW(), NOW()
+			FROM (VALUES ` + strings.Join(rows, ", ") + `) AS v (id, memory_id, user_id, content)
+			WHERE EXISTS (
+				SELECT 1 FROM memories WHERE id = v.memory_id AND deleted_at IS NULL
+			)
+			RETURNING memory_id
+		`
+		if err := insertForMemories(ctx, tx, query, memoryIDs, args); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 func (r *CommentRepo) GetCommentByID(ctx context.Context, id string) (*memory.Comment, error) {
 	var (
 		commentModel memory.Comment
 		created_at   sql.NullTime
+		updated_at   sql.NullTime
 	)
 	query := `
 		SELECT 
@@ -63,9 +129,11 @@ func (r *CommentRepo) GetCommentByID(ctx context.Context, id string) (*memory.Co
 			memory_id,
 			user_id,
 			content,
-			created_at
+			created_at,
+			updated_at,
+			version
 		FROM comments
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
@@ -74,6 +142,8 @@ func (r *CommentRepo) GetCommentByID(ctx context.Context, id string) (*memory.Co
 		&commentModel.UserId,
 		&commentModel.Content,
 		&created_at,
+		&updated_at,
+		&commentModel.Version,
 	)
 
 	if err != nil {
@@ -83,45 +153,47 @@ func (r *CommentRepo) GetCommentByID(ctx context.Context, id string) (*memory.Co
 		return nil, err
 	}
 	commentModel.CreatedAt = helper.DateToString(created_at)
+	commentModel.UpdatedAt = helper.DateToString(updated_at)
 	return &commentModel, nil
 }
 
-func (r *CommentRepo) GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) ([]*memory.Comment, error) {
-	var args []interface{}
-	count := 1
+func (r *CommentRepo) GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	filter, args := commentFilter(req)
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM comments WHERE deleted_at IS NULL ` + filter
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last row of the previous
+		// page, so rows inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		filter += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Time, cursor.ID)
+		offset = 0
+	}
+
+	// One extra row tells us whether there is a next page.
 	query := `
 		SELECT 
 			id,
 			memory_id,
 			user_id,
 			content,
-			created_at
+			created_at,
+			updated_at,
+			version
 		FROM 
 			comments
-		WHERE 1=1
-	`
-
-	filter := ""
-
-	if req.MemoryId != "" {
-		filter += fmt.Sprintf(" AND memory_id = $%d", count)
-		args = append(args, req.MemoryId)
-		count++
-	}
-
-	if req.UserId != "" {
-		filter += fmt.Sprintf(" AND user_id = $%d", count)
-		args = append(args, req.UserId)
-		count++
-	}
-
-	if req.Content != "" {
-		filter += fmt.Sprintf(" AND content ILIKE $%d", count)
-		args = append(args, "%"+req.Content+"%")
-		count++
-	}
-
-	query += filter
+		WHERE deleted_at IS NULL
+	` + filter + fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit+1, offset)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -129,12 +201,17 @@ func (r *CommentRepo) GetAllComments(ctx context.Context, req *memory.GetAllComm
 	}
 	defer rows.Close()
 
-	var commentList []*memory.Comment
+	var (
+		commentList   []*memory.Comment
+		lastCreatedAt time.Time
+		nextPageToken string
+	)
 
 	for rows.Next() {
 		var (
 			commentModel memory.Comment
 			created_at   sql.NullTime
+			updated_at   sql.NullTime
 		)
 		err = rows.Scan(
 			&commentModel.Id,
@@ -142,34 +219,84 @@ func (r *CommentRepo) GetAllComments(ctx context.Context, req *memory.GetAllComm
 			&commentModel.UserId,
 			&commentModel.Content,
 			&created_at,
+			&updated_at,
+			&commentModel.Version,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if int32(len(commentList)) == limit {
+			nextPageToken = helper.EncodeCursor(lastCreatedAt, commentList[len(commentList)-1].Id)
+			break
+		}
 		commentModel.CreatedAt = helper.DateToString(created_at)
+		commentModel.UpdatedAt = helper.DateToString(updated_at)
 		commentList = append(commentList, &commentModel)
+		lastCreatedAt = created_at.Time
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return commentList, nil
+	return &memory.GetAllCommentsResponse{
+		Comments:      commentList,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// commentFilter builds the WHERE conditions shared by the list and count
+// queries of GetAllComments.
+func commentFilter(req *memory.GetAllCommentsRequest) (string, []interface{}) {
+	var args []interface{}
+	count := 1
+	filter := ""
+
+	if req.MemoryId != "" {
+		filter += fmt.Sprintf(" AND memory_id = $%d", count)
+		args = append(args, req.MemoryId)
+		count++
+	}
+
+	if req.UserId != "" {
+		filter += fmt.Sprintf(" AND user_id = $%d", count)
+		args = append(args, req.UserId)
+		count++
+	}
+
+	if req.Content != "" {
+		filter += fmt.Sprintf(" AND content ILIKE $%d", count)
+		args = append(args, "%"+req.Content+"%")
+		count++
+	}
+
+	return filter, args
 }
 
 func (r *CommentRepo) UpdateComment(ctx context.Context, comment *models.UpdateCommentModel) error {
+	if err := ensureMemoryExists(ctx, r.db, comment.MemoryID); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE comments
 		SET 
 			memory_id = $1,
 			user_id = $2,
 			content = $3,
-			created_at = $4
-		WHERE id = $5
+			created_at = COALESCE($4, created_at),
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $5 AND deleted_at IS NULL AND ($6::bigint = 0 OR version = $6)
 	`
 
 	result, err := r.db.Exec(ctx, query,
 		comment.MemoryID,
 		comment.UserID,
 		comment.Content,
-		comment.Created,
+		optionalTime(comment.Created),
 		comment.ID,
+		comment.ExpectedVersion,
 	)
 
 	if err != nil {
@@ -177,13 +304,19 @@ func (r *CommentRepo) UpdateComment(ctx context.Context, comment *models.UpdateC
 	}
 
 	if result.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+		return missingOrConflict(ctx, r.db, "comments", comment.ID, comment.ExpectedVersion)
 	}
 
 	return nil
 }
 
 func (r *CommentRepo) PatchComment(ctx context.Context, comment *models.PatchCommentModel) error {
+	if comment.MemoryID != nil {
+		if err := ensureMemoryExists(ctx, r.db, *comment.MemoryID); err != nil {
+			return err
+		}
+	}
+
 	var args []interface{}
 	count := 1
 	query := `
@@ -218,12 +351,13 @@ func (r *CommentRepo) PatchComment(ctx context.Context, comment *models.PatchCom
 	}
 
 	if filter == "" {
-		return fmt.Errorf("at least one field to update is required")
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
 	}
 
-	filter = filter[:len(filter)-2] // Remove the trailing comma and space
-	query += filter + fmt.Sprintf(" WHERE id = $%d", count)
-	args = append(args, comment.ID)
+	// Every write bumps the version; a non-zero expected version must match.
+	filter += " updated_at = NOW(), version = version + 1"
+	query += filter + fmt.Sprintf(" WHERE id = $%d AND deleted_at IS NULL AND ($%d::bigint = 0 OR version = $%d)", count, count+1, count+1)
+	args = append(args, comment.ID, comment.ExpectedVersion)
 
 	result, err := r.db.Exec(ctx, query, args...)
 	if err != nil {
@@ -231,16 +365,18 @@ func (r *CommentRepo) PatchComment(ctx context.Context, comment *models.PatchCom
 	}
 
 	if result.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+		return missingOrConflict(ctx, r.db, "comments", comment.ID, comment.ExpectedVersion)
 	}
 
 	return nil
 }
 
+// DeleteComment moves a comment to the trash.
 func (r *CommentRepo) DeleteComment(ctx context.Context, id string) error {
 	query := `
-		DELETE FROM comments
-		WHERE id = $1
+		UPDATE comments
+		SET deleted_at = NOW(), updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.Exec(ctx, query, id)
@@ -254,3 +390,132 @@ func (r *CommentRepo) DeleteComment(ctx context.Context, id string) error {
 
 	return nil
 }
+
+func (r *CommentRepo) GetDeletedComments(ctx context.Context, req *memory.GetDeletedCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	var args []interface{}
+	count := 1
+	filter := ""
+
+	if req.MemoryId != "" {
+		filter += fmt.Sprintf(" AND memory_id = $%d", count)
+		args = append(args, req.MemoryId)
+		count++
+	}
+
+	if req.UserId != "" {
+		filter += fmt.Sprintf(" AND user_id = $%d", count)
+		args = append(args, req.UserId)
+		count++
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM comments WHERE deleted_at IS NOT NULL ` + filter
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	query := `
+		SELECT 
+			id,
+			memory_id,
+			user_id,
+			content,
+			created_at,
+			updated_at,
+			deleted_at,
+			version
+		FROM 
+			comments
+		WHERE deleted_at IS NOT NULL
+	` + filter + fmt.Sprintf(" ORDER BY deleted_at DESC, id DESC LIMIT $%d OFFSET $%d", count, count+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commentList []*memory.Comment
+
+	for rows.Next() {
+		var (
+			commentModel memory.Comment
+			created_at   sql.NullTime
+			updated_at   sql.NullTime
+			deleted_at   sql.NullTime
+		)
+		err = rows.Scan(
+			&commentModel.Id,
+			&commentModel.MemoryId,
+			&commentModel.UserId,
+			&commentModel.Content,
+			&created_at,
+			&updated_at,
+			&deleted_at,
+			&commentModel.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		commentModel.CreatedAt = helper.DateToString(created_at)
+		commentModel.UpdatedAt = helper.DateToString(updated_at)
+		commentModel.DeletedAt = helper.DateToString(deleted_at)
+		commentList = append(commentList, &commentModel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllCommentsResponse{
+		Comments: commentList,
+		Count:    total,
+	}, nil
+}
+
+// RestoreComment takes a comment out of the trash. A comment whose memory is
+// still in the trash cannot be restored on its own.
+func (r *CommentRepo) RestoreComment(ctx context.Context, id string) error {
+	var memoryDeleted bool
+	err := r.db.QueryRow(ctx, `
+		SELECT m.deleted_at IS NOT NULL
+		FROM comments c
+		JOIN memories m ON m.id = c.memory_id
+		WHERE c.id = $1 AND c.deleted_at IS NOT NULL
+	`, id).Scan(&memoryDeleted)
+	if err != nil {
+		return err
+	}
+	if memoryDeleted {
+		return storage.Errorf(storage.ErrConflict, "cannot restore comment %s: its memory is in the trash", id)
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE comments
+		SET deleted_at = NULL, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// PurgeComments permanently deletes comments that were trashed before the given time.
+func (r *CommentRepo) PurgeComments(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM comments
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
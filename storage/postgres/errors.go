@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// classifier maps PostgreSQL errors onto the storage error kinds.
+var classifier = storage.Classifier(errorKind)
+
+func errorKind(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch code := pgErr.Code; {
+		case code == "23505": // unique_violation
+			return storage.ErrAlreadyExists
+		case code == "23503": // foreign_key_violation
+			return storage.ErrNotFound
+		case code == "40001", code == "40P01": // serialization_failure, deadlock_detected
+			return storage.ErrConflict
+		case strings.HasPrefix(code, "22"), // data_exception, e.g. a malformed uuid
+			code == "23502", code == "23514": // not_null_violation, check_violation
+			return storage.ErrInvalidArgument
+		case strings.HasPrefix(code, "08"), // connection_exception
+			strings.HasPrefix(code, "53"),  // insufficient_resources
+			strings.HasPrefix(code, "57P"): // operator intervention, e.g. a shutdown
+			return storage.ErrUnavailable
+		}
+		return nil
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) {
+		return storage.ErrUnavailable
+	}
+	return nil
+}
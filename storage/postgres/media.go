@@ -3,20 +3,24 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/time_capsule/memory-service/genproto/memory"
 	"github.com/time_capsule/memory-service/helper"
 	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
 )
 
 type MediaRepo struct {
-	db *pgx.Conn
+	db querier
 }
 
-func NewMediaRepo(db *pgx.Conn) *MediaRepo {
+func NewMediaRepo(db querier) *MediaRepo {
 	return &MediaRepo{
 		db: db,
 	}
@@ -33,9 +37,12 @@ func (r *MediaRepo) CreateMedia(ctx context.Context, media *models.CreateMediaMo
 			type,
 			url,
 			created_at
-		) VALUES (
-			$1, $2, $3, $4, NOW()
-		) RETURNING id
+		)
+		SELECT $1, $2, $3, $4, NOW()
+		WHERE EXISTS (
+			SELECT 1 FROM memories WHERE id = $2 AND deleted_at IS NULL
+		)
+		RETURNING id
 	`
 
 	err := r.db.QueryRow(ctx, query,
@@ -46,12 +53,68 @@ func (r *MediaRepo) CreateMedia(ctx context.Context, media *models.CreateMediaMo
 	).Scan(&media.ID)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, media.MemoryID)
+		}
 		return "", err
 	}
 
 	return media.ID, nil
 }
 
+// BulkCreateMedia inserts the media with multi-row statements in one
+// transaction. Like CreateMedia it refuses media of missing or trashed
+// memories.
+func (r *MediaRepo) BulkCreateMedia(ctx context.Context, media []*models.CreateMediaModel) ([]string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ids := make([]string, 0, len(media))
+	for start := 0; start < len(media); start += bulkRows {
+		chunk := media[start:min(start+bulkRows, len(media))]
+		rows := make([]string, len(chunk))
+		memoryIDs := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*4)
+		for i, m := range chunk {
+			if m.ID == "" {
+				m.ID = uuid.NewString()
+			}
+			rows[i] = fmt.Sprintf(`($%d::uuid, $%d::uuid, $%d, $%d)`, rowParams(i, 4)...)
+			memoryIDs[i] = m.MemoryID
+			args = append(args, m.ID, m.MemoryID, m.Type, m.URL)
+			ids = append(ids, m.ID)
+		}
+
+		query := `
+			INSERT INTO media (
+				id,
+				memory_id,
+				type,
+				url,
+				created_at
+			)
+			SELECT v.id, v.memory_id, v.type, v.url, NOW()
+			FROM (VALUES ` + strings.Join(rows, ", ") + `) AS v (id, memory_id, type, url)
+			WHERE EXISTS (
+				SELECT 1 FROM memories WHERE id = v.memory_id AND deleted_at IS NULL
+			)
+			RETURNING memory_id
+		`
+		if err := insertForMemories(ctx, tx, query, memoryIDs, args); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 func (r *MediaRepo) GetMediaByID(ctx context.Context, id string) (*memory.Media, error) {
 	var (
 		mediaModel memory.Media
@@ -65,7 +128,7 @@ func (r *MediaRepo) GetMediaByID(ctx context.Context, id string) (*memory.Media,
 			url,
 			created_at
 		FROM media
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
@@ -86,9 +149,29 @@ func (r *MediaRepo) GetMediaByID(ctx context.Context, id string) (*memory.Media,
 	return &mediaModel, nil
 }
 
-func (r *MediaRepo) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) ([]*memory.Media, error) {
-	var args []interface{}
-	count := 1
+func (r *MediaRepo) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) (*memory.GetAllMediaResponse, error) {
+	filter, args := mediaFilter(req)
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM media WHERE deleted_at IS NULL ` + filter
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last row of the previous
+		// page, so rows inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		filter += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Time, cursor.ID)
+		offset = 0
+	}
+
+	// One extra row tells us whether there is a next page.
 	query := `
 		SELECT 
 			id,
@@ -98,24 +181,9 @@ func (r *MediaRepo) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequ
 			created_at
 		FROM 
 			media
-		WHERE 1=1
-	`
-
-	filter := ""
-
-	if req.MemoryId != "" {
-		filter += fmt.Sprintf(" AND memory_id = $%d", count)
-		args = append(args, req.MemoryId)
-		count++
-	}
-
-	if len(req.Type) > 0 {
-		filter += fmt.Sprintf(" AND type ILIKE $%d", count)
-		args = append(args, "%"+req.Type+"%")
-		count++
-	}
-
-	query += filter
+		WHERE deleted_at IS NULL
+	` + filter + fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit+1, offset)
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -123,7 +191,11 @@ func (r *MediaRepo) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequ
 	}
 	defer rows.Close()
 
-	var mediaList []*memory.Media
+	var (
+		mediaList     []*memory.Media
+		lastCreatedAt time.Time
+		nextPageToken string
+	)
 
 	for rows.Next() {
 		var (
@@ -140,29 +212,67 @@ func (r *MediaRepo) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequ
 		if err != nil {
 			return nil, err
 		}
+		if int32(len(mediaList)) == limit {
+			nextPageToken = helper.EncodeCursor(lastCreatedAt, mediaList[len(mediaList)-1].Id)
+			break
+		}
 		mediaModel.CreatedAt = helper.DateToString(created_at)
 		mediaList = append(mediaList, &mediaModel)
+		lastCreatedAt = created_at.Time
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMediaResponse{
+		Media:         mediaList,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// mediaFilter builds the WHERE conditions shared by the list and count
+// queries of GetAllMedia.
+func mediaFilter(req *memory.GetAllMediaRequest) (string, []interface{}) {
+	var args []interface{}
+	count := 1
+	filter := ""
+
+	if req.MemoryId != "" {
+		filter += fmt.Sprintf(" AND memory_id = $%d", count)
+		args = append(args, req.MemoryId)
+		count++
+	}
+
+	if len(req.Type) > 0 {
+		filter += fmt.Sprintf(" AND type ILIKE $%d", count)
+		args = append(args, "%"+req.Type+"%")
+		count++
 	}
 
-	return mediaList, nil
+	return filter, args
 }
 
 func (r *MediaRepo) UpdateMedia(ctx context.Context, media *models.UpdateMediaModel) error {
+	if err := ensureMemoryExists(ctx, r.db, media.MemoryID); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE media
 		SET 
 			memory_id = $1,
 			type = $2,
 			url = $3,
-			created_at = $4
-		WHERE id = $5
+			created_at = COALESCE($4, created_at)
+		WHERE id = $5 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.Exec(ctx, query,
 		media.MemoryID,
 		media.Type,
 		media.URL,
-		media.Created,
+		optionalTime(media.Created),
 		media.ID,
 	)
 
@@ -178,6 +288,12 @@ func (r *MediaRepo) UpdateMedia(ctx context.Context, media *models.UpdateMediaMo
 }
 
 func (r *MediaRepo) PatchMedia(ctx context.Context, media *models.PatchMediaModel) error {
+	if media.MemoryID != nil {
+		if err := ensureMemoryExists(ctx, r.db, *media.MemoryID); err != nil {
+			return err
+		}
+	}
+
 	var args []interface{}
 	count := 1
 	query := `
@@ -212,11 +328,11 @@ func (r *MediaRepo) PatchMedia(ctx context.Context, media *models.PatchMediaMode
 	}
 
 	if filter == "" {
-		return fmt.Errorf("at least one field to update is required")
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
 	}
 
 	filter = filter[:len(filter)-2] // Remove the trailing comma and space
-	query += filter + fmt.Sprintf(" WHERE id = $%d", count)
+	query += filter + fmt.Sprintf(" WHERE id = $%d AND deleted_at IS NULL", count)
 	args = append(args, media.ID)
 
 	result, err := r.db.Exec(ctx, query, args...)
@@ -231,10 +347,12 @@ func (r *MediaRepo) PatchMedia(ctx context.Context, media *models.PatchMediaMode
 	return nil
 }
 
+// DeleteMedia moves media to the trash.
 func (r *MediaRepo) DeleteMedia(ctx context.Context, id string) error {
 	query := `
-		DELETE FROM media
-		WHERE id = $1
+		UPDATE media
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.Exec(ctx, query, id)
@@ -248,3 +366,120 @@ func (r *MediaRepo) DeleteMedia(ctx context.Context, id string) error {
 
 	return nil
 }
+
+func (r *MediaRepo) GetDeletedMedia(ctx context.Context, req *memory.GetDeletedMediaRequest) (*memory.GetAllMediaResponse, error) {
+	var args []interface{}
+	count := 1
+	filter := ""
+
+	if req.MemoryId != "" {
+		filter += fmt.Sprintf(" AND memory_id = $%d", count)
+		args = append(args, req.MemoryId)
+		count++
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM media WHERE deleted_at IS NOT NULL ` + filter
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	query := `
+		SELECT 
+			id,
+			memory_id,
+			type,
+			url,
+			created_at,
+			deleted_at
+		FROM 
+			media
+		WHERE deleted_at IS NOT NULL
+	` + filter + fmt.Sprintf(" ORDER BY deleted_at DESC, id DESC LIMIT $%d OFFSET $%d", count, count+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mediaList []*memory.Media
+
+	for rows.Next() {
+		var (
+			mediaModel memory.Media
+			created_at sql.NullTime
+			deleted_at sql.NullTime
+		)
+		err = rows.Scan(
+			&mediaModel.Id,
+			&mediaModel.MemoryId,
+			&mediaModel.Type,
+			&mediaModel.Url,
+			&created_at,
+			&deleted_at,
+		)
+		if err != nil {
+			return nil, err
+		}
+		mediaModel.CreatedAt = helper.DateToString(created_at)
+		mediaModel.DeletedAt = helper.DateToString(deleted_at)
+		mediaList = append(mediaList, &mediaModel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMediaResponse{
+		Media: mediaList,
+		Count: total,
+	}, nil
+}
+
+// RestoreMedia takes media out of the trash. Media whose memory is still
+// in the trash cannot be restored on its own.
+func (r *MediaRepo) RestoreMedia(ctx context.Context, id string) error {
+	var memoryDeleted bool
+	err := r.db.QueryRow(ctx, `
+		SELECT m.deleted_at IS NOT NULL
+		FROM media md
+		JOIN memories m ON m.id = md.memory_id
+		WHERE md.id = $1 AND md.deleted_at IS NOT NULL
+	`, id).Scan(&memoryDeleted)
+	if err != nil {
+		return err
+	}
+	if memoryDeleted {
+		return storage.Errorf(storage.ErrConflict, "cannot restore media %s: its memory is in the trash", id)
+	}
+
+	result, err := r.db.Exec(ctx, `
+		UPDATE media
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// PurgeMedia permanently deletes media that were trashed before the given time.
+func (r *MediaRepo) PurgeMedia(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM media
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -3,7 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,18 +13,40 @@ import (
 	"github.com/time_capsule/memory-service/genproto/memory"
 	"github.com/time_capsule/memory-service/helper"
 	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
 )
 
+// defaultSearchLanguage is the text search configuration used for memories
+// and search terms that do not specify one.
+const defaultSearchLanguage = "english"
+
 type MemoryRepo struct {
-	db *pgx.Conn
+	db querier
 }
 
-func NewMemoryRepo(db *pgx.Conn) *MemoryRepo {
+func NewMemoryRepo(db querier) *MemoryRepo {
 	return &MemoryRepo{
 		db: db,
 	}
 }
 
+// ensureMemoryExists returns storage.ErrMemoryNotFound unless the memory
+// exists and is not in the trash. Media and comments use it to refuse
+// pointing at missing memories.
+func ensureMemoryExists(ctx context.Context, db querier, id string) error {
+	var exists bool
+	err := db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM memories WHERE id = $1 AND deleted_at IS NULL)
+	`, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, id)
+	}
+	return nil
+}
+
 func (r *MemoryRepo) CreateMemory(ctx context.Context, memory *models.CreateMemoryModel) (string, error) {
 	if memory.ID == "" {
 		memory.ID = uuid.NewString()
@@ -39,9 +63,11 @@ func (r *MemoryRepo) CreateMemory(ctx context.Context, memory *models.CreateMemo
 			longitude,
 			place_name,
 			privacy,
-			created_at
+			language,
+			created_at,
+			updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW()
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE(NULLIF($11, ''), $12)::regconfig, NOW(), NOW()
 		) RETURNING id
 	`
 
@@ -56,6 +82,8 @@ func (r *MemoryRepo) CreateMemory(ctx context.Context, memory *models.CreateMemo
 		memory.Longitude,
 		memory.PlaceName,
 		memory.Privacy,
+		memory.Language,
+		defaultSearchLanguage,
 	).Scan(&memory.ID)
 
 	if err != nil {
@@ -65,11 +93,76 @@ func (r *MemoryRepo) CreateMemory(ctx context.Context, memory *models.CreateMemo
 	return memory.ID, nil
 }
 
+// BulkCreateMemories inserts the memories with multi-row statements in one
+// transaction.
+func (r *MemoryRepo) BulkCreateMemories(ctx context.Context, memories []*models.CreateMemoryModel) ([]string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ids := make([]string, 0, len(memories))
+	for start := 0; start < len(memories); start += bulkRows {
+		chunk := memories[start:min(start+bulkRows, len(memories))]
+		rows := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*12)
+		for i, memory := range chunk {
+			if memory.ID == "" {
+				memory.ID = uuid.NewString()
+			}
+			rows[i] = fmt.Sprintf(`($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, COALESCE(NULLIF($%d, ''), $%d)::regconfig, NOW(), NOW())`, rowParams(i, 12)...)
+			args = append(args,
+				memory.ID,
+				memory.UserID,
+				memory.Title,
+				memory.Description,
+				memory.Date,
+				memory.Tags,
+				memory.Latitude,
+				memory.Longitude,
+				memory.PlaceName,
+				memory.Privacy,
+				memory.Language,
+				defaultSearchLanguage,
+			)
+			ids = append(ids, memory.ID)
+		}
+
+		query := `
+			INSERT INTO memories (
+				id,
+				user_id,
+				title,
+				description,
+				date,
+				tags,
+				latitude,
+				longitude,
+				place_name,
+				privacy,
+				language,
+				created_at,
+				updated_at
+			) VALUES ` + strings.Join(rows, ", ")
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 func (r *MemoryRepo) GetMemoryByID(ctx context.Context, id string) (*memory.Memory, error) {
 	var (
 		memoryModel memory.Memory
 		date        sql.NullTime
 		created_at  sql.NullTime
+		updated_at  sql.NullTime
 		tags        []string
 	)
 	query := `
@@ -84,9 +177,12 @@ func (r *MemoryRepo) GetMemoryByID(ctx context.Context, id string) (*memory.Memo
 			longitude,
 			place_name,
 			privacy,
-			created_at
+			language::text,
+			created_at,
+			updated_at,
+			version
 		FROM memories
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
@@ -100,7 +196,10 @@ func (r *MemoryRepo) GetMemoryByID(ctx context.Context, id string) (*memory.Memo
 		&memoryModel.Longitude,
 		&memoryModel.PlaceName,
 		&memoryModel.Privacy,
+		&memoryModel.Language,
 		&created_at,
+		&updated_at,
+		&memoryModel.Version,
 	)
 
 	if err != nil {
@@ -113,12 +212,74 @@ func (r *MemoryRepo) GetMemoryByID(ctx context.Context, id string) (*memory.Memo
 	memoryModel.Tags = tags
 	memoryModel.Date = helper.DateToString(date)
 	memoryModel.CreatedAt = helper.DateToString(created_at)
+	memoryModel.UpdatedAt = helper.DateToString(updated_at)
 	return &memoryModel, nil
 }
 
-func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) ([]*memory.Memory, error) {
-	var args []interface{}
-	count := 1
+func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	cond, err := memoryFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	filter, args := cond.filter, cond.args
+
+	// Relevance and distance orderings are not keyset-friendly, so they are
+	// paginated with page/limit only.
+	orderBy := " ORDER BY date DESC, id DESC"
+	keyset := true
+	switch req.SortBy {
+	case "", "date":
+	case "relevance":
+		if cond.searchQuery == "" {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "sort by relevance requires a search term")
+		}
+		orderBy = " ORDER BY search_rank DESC, date DESC, id DESC"
+		keyset = false
+	case "distance":
+		if cond.center == nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "sort by distance requires a center point")
+		}
+		orderBy = " ORDER BY distance_meters ASC, id DESC"
+		keyset = false
+	default:
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid sort_by %q", req.SortBy)
+	}
+	if !keyset && req.PageToken != "" {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "page tokens are only supported when sorting by date")
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM memories WHERE deleted_at IS NULL ` + filter
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last row of the previous
+		// page, so rows inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		filter += fmt.Sprintf(" AND (date, id) < ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, cursor.Time, cursor.ID)
+		offset = 0
+	}
+
+	// Rank and highlight only when searching; otherwise return neutral values.
+	searchColumns := `0::float8 AS search_rank, ''::text AS highlight`
+	if cond.searchQuery != "" {
+		searchColumns = fmt.Sprintf(`ts_rank_cd(search_vector, %[1]s)::float8 AS search_rank,
+			ts_headline(language, title || ' ' || description, %[1]s, 'MaxFragments=2, MinWords=5, MaxWords=20') AS highlight`, cond.searchQuery)
+	}
+	distanceColumn := `0::float8 AS distance_meters`
+	if cond.center != nil {
+		distanceColumn = distanceExpr(len(args)+1, len(args)+2) + ` AS distance_meters`
+		args = append(args, cond.center[0], cond.center[1])
+	}
+
+	// One extra row tells us whether there is a next page.
 	query := `
 		SELECT
 			id,
@@ -131,13 +292,111 @@ func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemor
 			longitude,
 			place_name,
 			privacy,
-			created_at
+			language::text,
+			` + searchColumns + `,
+			` + distanceColumn + `,
+			created_at,
+			updated_at,
+			version
 		FROM 
 			memories
-		WHERE 1=1 
-	`
+		WHERE deleted_at IS NULL 
+	` + filter + orderBy + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit+1, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		memories      []*memory.Memory
+		lastDate      time.Time
+		nextPageToken string
+	)
+
+	for rows.Next() {
+		var (
+			memoryModel memory.Memory
+			date        sql.NullTime
+			created_at  sql.NullTime
+			updated_at  sql.NullTime
+			tags        []string
+		)
+		err = rows.Scan(
+			&memoryModel.Id,
+			&memoryModel.UserId,
+			&memoryModel.Title,
+			&memoryModel.Description,
+			&date,
+			&tags,
+			&memoryModel.Latitude,
+			&memoryModel.Longitude,
+			&memoryModel.PlaceName,
+			&memoryModel.Privacy,
+			&memoryModel.Language,
+			&memoryModel.SearchRank,
+			&memoryModel.Highlight,
+			&memoryModel.DistanceMeters,
+			&created_at,
+			&updated_at,
+			&memoryModel.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if int32(len(memories)) == limit {
+			if keyset {
+				nextPageToken = helper.EncodeCursor(lastDate, memories[len(memories)-1].Id)
+			}
+			break
+		}
+		memoryModel.Tags = tags
+		memoryModel.Date = helper.DateToString(date)
+		memoryModel.CreatedAt = helper.DateToString(created_at)
+		memoryModel.UpdatedAt = helper.DateToString(updated_at)
+		memories = append(memories, &memoryModel)
+		lastDate = date.Time
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMemoriesResponse{
+		Memories:      memories,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// memoryConditions holds the WHERE conditions of a GetAllMemories request
+// along with the expressions needed to rank and measure the matches.
+type memoryConditions struct {
+	filter      string
+	args        []interface{}
+	searchQuery string      // tsquery expression, set when searching
+	center      *[2]float64 // latitude/longitude of the requested center point
+}
 
+// memoryFilter builds the WHERE conditions shared by the list and count
+// queries of GetAllMemories.
+func memoryFilter(req *memory.GetAllMemoriesRequest) (*memoryConditions, error) {
+	var args []interface{}
+	count := 1
 	filter := ""
+	cond := &memoryConditions{}
+
+	if req.SearchTerm != "" {
+		language := req.SearchLanguage
+		if language == "" {
+			language = defaultSearchLanguage
+		}
+		cond.searchQuery = fmt.Sprintf("websearch_to_tsquery($%d::regconfig, $%d)", count, count+1)
+		filter += " AND search_vector @@ " + cond.searchQuery
+		args = append(args, language, req.SearchTerm)
+		count += 2
+	}
 
 	if req.UserId != "" {
 		filter += fmt.Sprintf(" AND user_id = $%d", count)
@@ -165,7 +424,7 @@ func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemor
 	if req.StartDate != "" {
 		startTime, err := time.Parse(time.RFC3339, req.StartDate)
 		if err != nil {
-			return nil, fmt.Errorf("invalid start time format: %w", err)
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid start time format: %w", err)
 		}
 		filter += fmt.Sprintf(" AND date >= $%d", count)
 		args = append(args, startTime)
@@ -175,25 +434,91 @@ func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemor
 	if req.EndDate != "" {
 		endTime, err := time.Parse(time.RFC3339, req.EndDate)
 		if err != nil {
-			return nil, fmt.Errorf("invalid end time format: %w", err)
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid end time format: %w", err)
 		}
 		filter += fmt.Sprintf(" AND date <= $%d", count)
 		args = append(args, endTime)
 		count++
 	}
 
-	if req.Latitude != 0 {
+	if req.Latitude != nil {
 		filter += fmt.Sprintf(" AND latitude = $%d", count)
-		args = append(args, req.Latitude)
+		args = append(args, *req.Latitude)
 		count++
 	}
 
-	if req.Longitude != 0 {
+	if req.Longitude != nil {
 		filter += fmt.Sprintf(" AND longitude = $%d", count)
-		args = append(args, req.Longitude)
+		args = append(args, *req.Longitude)
 		count++
 	}
 
+	if (req.CenterLatitude == nil) != (req.CenterLongitude == nil) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "center_latitude and center_longitude must be set together")
+	}
+	if req.RadiusMeters < 0 {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "radius_meters must not be negative")
+	}
+	if req.RadiusMeters > 0 && req.CenterLatitude == nil {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "radius_meters requires center_latitude and center_longitude")
+	}
+	if req.CenterLatitude != nil {
+		lat, lon := *req.CenterLatitude, *req.CenterLongitude
+		if err := helper.ValidateCoordinates(lat, lon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid center point: %w", err)
+		}
+
+		cond.center = &[2]float64{lat, lon}
+
+		filter += " AND latitude IS NOT NULL AND longitude IS NOT NULL"
+		if req.RadiusMeters > 0 {
+			// Cheap bounding-box pre-filter (index friendly) before the exact distance check.
+			minLat, minLon, maxLat, maxLon, ok := helper.BoundingBox(lat, lon, req.RadiusMeters)
+			filter += fmt.Sprintf(" AND latitude BETWEEN $%d AND $%d", count, count+1)
+			args = append(args, minLat, maxLat)
+			count += 2
+			if ok {
+				filter += longitudeRange(count, minLon, maxLon)
+				args = append(args, minLon, maxLon)
+				count += 2
+			}
+
+			filter += fmt.Sprintf(" AND %s <= $%d", distanceExpr(count, count+1), count+2)
+			args = append(args, lat, lon, req.RadiusMeters)
+			count += 3
+		}
+	}
+
+	bbox := []*float64{req.MinLatitude, req.MinLongitude, req.MaxLatitude, req.MaxLongitude}
+	bboxSet := 0
+	for _, v := range bbox {
+		if v != nil {
+			bboxSet++
+		}
+	}
+	if bboxSet != 0 && bboxSet != len(bbox) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "bounding box requires min/max latitude and longitude")
+	}
+	if bboxSet == len(bbox) {
+		minLat, minLon, maxLat, maxLon := *req.MinLatitude, *req.MinLongitude, *req.MaxLatitude, *req.MaxLongitude
+		if err := helper.ValidateCoordinates(minLat, minLon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: %w", err)
+		}
+		if err := helper.ValidateCoordinates(maxLat, maxLon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: %w", err)
+		}
+		if minLat > maxLat {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: min_latitude is greater than max_latitude")
+		}
+
+		filter += fmt.Sprintf(" AND latitude BETWEEN $%d AND $%d", count, count+1)
+		args = append(args, minLat, maxLat)
+		count += 2
+		filter += longitudeRange(count, minLon, maxLon)
+		args = append(args, minLon, maxLon)
+		count += 2
+	}
+
 	if req.PlaceName != "" {
 		filter += fmt.Sprintf(" AND place_name ILIKE $%d", count)
 		args = append(args, "%"+req.PlaceName+"%")
@@ -206,48 +531,41 @@ func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemor
 		count++
 	}
 
-	query += filter
-	rows, err := r.db.Query(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	cond.filter = filter
+	cond.args = args
+	return cond, nil
+}
 
-	var memories []*memory.Memory
+// distanceExpr returns the haversine distance in meters between a memory and
+// the point given by the placeholders $latParam and $lonParam.
+func distanceExpr(latParam, lonParam int) string {
+	return fmt.Sprintf(`(%[1]f * 2 * asin(least(1, sqrt(
+			power(sin(radians(latitude - $%[2]d::float8) / 2), 2) +
+			cos(radians($%[2]d::float8)) * cos(radians(latitude)) * power(sin(radians(longitude - $%[3]d::float8) / 2), 2)))))`,
+		helper.EarthRadiusMeters, latParam, lonParam)
+}
 
-	for rows.Next() {
-		var (
-			memoryModel memory.Memory
-			date        sql.NullTime
-			created_at  sql.NullTime
-			tags        []string
-		)
-		err = rows.Scan(
-			&memoryModel.Id,
-			&memoryModel.UserId,
-			&memoryModel.Title,
-			&memoryModel.Description,
-			&date,
-			&tags,
-			&memoryModel.Latitude,
-			&memoryModel.Longitude,
-			&memoryModel.PlaceName,
-			&memoryModel.Privacy,
-			&created_at,
-		)
-		if err != nil {
-			return nil, err
-		}
-		memoryModel.Tags = tags
-		memoryModel.Date = helper.DateToString(date)
-		memoryModel.CreatedAt = helper.DateToString(created_at)
-		memories = append(memories, &memoryModel)
+// longitudeRange returns the longitude condition for the placeholders $n and
+// $n+1. A west edge greater than the east edge means the range crosses the
+// antimeridian.
+func longitudeRange(n int, minLon, maxLon float64) string {
+	if minLon <= maxLon {
+		return fmt.Sprintf(" AND longitude BETWEEN $%d AND $%d", n, n+1)
 	}
-
-	return memories, nil
+	return fmt.Sprintf(" AND (longitude >= $%d OR longitude <= $%d)", n, n+1)
 }
 
 func (r *MemoryRepo) UpdateMemory(ctx context.Context, memory *models.UpdateMemoryModel) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setChangedBy(ctx, tx, memory.ChangedBy); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE memories
 		SET 
@@ -259,11 +577,14 @@ func (r *MemoryRepo) UpdateMemory(ctx context.Context, memory *models.UpdateMemo
 			latitude = $6,
 			longitude = $7,
 			place_name = $8,
-			privacy = $9
-		WHERE id = $10
+			privacy = $9,
+			language = COALESCE(NULLIF($10, '')::regconfig, language),
+			updated_at = NOW(),
+			version = version + 1
+		WHERE id = $11 AND deleted_at IS NULL AND ($12::bigint = 0 OR version = $12)
 	`
 
-	result, err := r.db.Exec(ctx, query,
+	result, err := tx.Exec(ctx, query,
 		memory.UserID,
 		memory.Title,
 		memory.Description,
@@ -273,7 +594,9 @@ func (r *MemoryRepo) UpdateMemory(ctx context.Context, memory *models.UpdateMemo
 		memory.Longitude,
 		memory.PlaceName,
 		memory.Privacy,
+		memory.Language,
 		memory.ID,
+		memory.ExpectedVersion,
 	)
 
 	if err != nil {
@@ -281,9 +604,9 @@ func (r *MemoryRepo) UpdateMemory(ctx context.Context, memory *models.UpdateMemo
 	}
 
 	if result.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+		return missingOrConflict(ctx, r.db, "memories", memory.ID, memory.ExpectedVersion)
 	}
-	return nil
+	return tx.Commit(ctx)
 }
 func (r *MemoryRepo) PatchMemory(ctx context.Context, memory *models.PatchMemoryModel) error {
 	var args []interface{}
@@ -344,39 +667,436 @@ func (r *MemoryRepo) PatchMemory(ctx context.Context, memory *models.PatchMemory
 		count++
 	}
 
+	if memory.Language != nil {
+		filter += fmt.Sprintf(" language = $%d::regconfig, ", count)
+		args = append(args, *memory.Language)
+		count++
+	}
+
 	if filter == "" {
-		return fmt.Errorf("at least one field to update is required")
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
 	}
 
-	filter = filter[:len(filter)-2]                         // Remove the trailing comma and space
-	query += filter + fmt.Sprintf(" WHERE id = $%d", count) // Append filter to WHERE clause
-	args = append(args, memory.ID)                          // Add memory ID to args
+	// Every write bumps the version; a non-zero expected version must match.
+	filter += " updated_at = NOW(), version = version + 1"
+	query += filter + fmt.Sprintf(" WHERE id = $%d AND deleted_at IS NULL AND ($%d::bigint = 0 OR version = $%d)", count, count+1, count+1)
+	args = append(args, memory.ID, memory.ExpectedVersion)
 
-	result, err := r.db.Exec(ctx, query, args...)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setChangedBy(ctx, tx, memory.ChangedBy); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
 	if err != nil {
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+		return missingOrConflict(ctx, r.db, "memories", memory.ID, memory.ExpectedVersion)
 	}
 
-	return nil
+	return tx.Commit(ctx)
+}
+
+// DeleteMemory moves a memory to the trash together with its media and
+// comments in a single transaction and reports how many children went with
+// it. All of them share the same deleted_at, so RestoreMemory can bring back
+// exactly the children that were trashed with the memory.
+func (r *MemoryRepo) DeleteMemory(ctx context.Context, id string) (*memory.DeleteMemoryResponse, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var deletedAt time.Time
+	err = tx.QueryRow(ctx, `
+		UPDATE memories
+		SET deleted_at = NOW(), updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING deleted_at
+	`, id).Scan(&deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := tx.Exec(ctx, `
+		UPDATE media
+		SET deleted_at = $2
+		WHERE memory_id = $1 AND deleted_at IS NULL
+	`, id, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := tx.Exec(ctx, `
+		UPDATE comments
+		SET deleted_at = $2, updated_at = $2, version = version + 1
+		WHERE memory_id = $1 AND deleted_at IS NULL
+	`, id, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &memory.DeleteMemoryResponse{
+		Success:         true,
+		DeletedMedia:    int32(media.RowsAffected()),
+		DeletedComments: int32(comments.RowsAffected()),
+	}, nil
 }
-func (r *MemoryRepo) DeleteMemory(ctx context.Context, id string) error {
+
+func (r *MemoryRepo) GetDeletedMemories(ctx context.Context, req *memory.GetDeletedMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	var args []interface{}
+	filter := ""
+
+	if req.UserId != "" {
+		filter += " AND user_id = $1"
+		args = append(args, req.UserId)
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM memories WHERE deleted_at IS NOT NULL ` + filter
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
 	query := `
-		DELETE FROM memories
+		SELECT
+			id,
+			user_id,
+			title,
+			description,
+			date,
+			tags,
+			latitude,
+			longitude,
+			place_name,
+			privacy,
+			language::text,
+			created_at,
+			updated_at,
+			deleted_at,
+			version
+		FROM 
+			memories
+		WHERE deleted_at IS NOT NULL 
+	` + filter + fmt.Sprintf(" ORDER BY deleted_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []*memory.Memory
+
+	for rows.Next() {
+		var (
+			memoryModel memory.Memory
+			date        sql.NullTime
+			created_at  sql.NullTime
+			updated_at  sql.NullTime
+			deleted_at  sql.NullTime
+			tags        []string
+		)
+		err = rows.Scan(
+			&memoryModel.Id,
+			&memoryModel.UserId,
+			&memoryModel.Title,
+			&memoryModel.Description,
+			&date,
+			&tags,
+			&memoryModel.Latitude,
+			&memoryModel.Longitude,
+			&memoryModel.PlaceName,
+			&memoryModel.Privacy,
+			&memoryModel.Language,
+			&created_at,
+			&updated_at,
+			&deleted_at,
+			&memoryModel.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		memoryModel.Tags = tags
+		memoryModel.Date = helper.DateToString(date)
+		memoryModel.CreatedAt = helper.DateToString(created_at)
+		memoryModel.UpdatedAt = helper.DateToString(updated_at)
+		memoryModel.DeletedAt = helper.DateToString(deleted_at)
+		memories = append(memories, &memoryModel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMemoriesResponse{
+		Memories: memories,
+		Count:    total,
+	}, nil
+}
+
+// RestoreMemory takes a memory out of the trash along with the media and
+// comments that were trashed together with it.
+func (r *MemoryRepo) RestoreMemory(ctx context.Context, id string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var deletedAt time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT deleted_at FROM memories
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		FOR UPDATE
+	`, id).Scan(&deletedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE memories
+		SET deleted_at = NULL, updated_at = NOW(), version = version + 1
 		WHERE id = $1
+	`, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE media
+		SET deleted_at = NULL
+		WHERE memory_id = $1 AND deleted_at = $2
+	`, id, deletedAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE comments
+		SET deleted_at = NULL, updated_at = NOW(), version = version + 1
+		WHERE memory_id = $1 AND deleted_at = $2
+	`, id, deletedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// PurgeMemories permanently deletes memories that were trashed before the
+// given time. Their media and comments are removed by ON DELETE CASCADE.
+func (r *MemoryRepo) PurgeMemories(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, `
+		DELETE FROM memories
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// ListMemoryRevisions lists the recorded changes of a memory, newest first.
+func (r *MemoryRepo) ListMemoryRevisions(ctx context.Context, req *memory.ListMemoryRevisionsRequest) (*memory.ListMemoryRevisionsResponse, error) {
+	var total int32
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM memory_revisions WHERE memory_id = $1`, req.MemoryId).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	query := `
+		SELECT
+			id,
+			memory_id,
+			version,
+			COALESCE(changed_by::text, ''),
+			changed_fields,
+			old_values,
+			new_values,
+			created_at
+		FROM memory_revisions
+		WHERE memory_id = $1
+		ORDER BY created_at DESC, version DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, req.MemoryId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*memory.MemoryRevision
+
+	for rows.Next() {
+		var (
+			revision      memory.MemoryRevision
+			changedFields []string
+			oldValues     map[string]json.RawMessage
+			newValues     map[string]json.RawMessage
+			created_at    sql.NullTime
+		)
+		err = rows.Scan(
+			&revision.Id,
+			&revision.MemoryId,
+			&revision.Version,
+			&revision.ChangedBy,
+			&changedFields,
+			&oldValues,
+			&newValues,
+			&created_at,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range changedFields {
+			revision.Changes = append(revision.Changes, &memory.FieldChange{
+				Field:    field,
+				OldValue: string(oldValues[field]),
+				NewValue: string(newValues[field]),
+			})
+		}
+		revision.CreatedAt = helper.DateToString(created_at)
+		revisions = append(revisions, &revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.ListMemoryRevisionsResponse{
+		Revisions: revisions,
+		Count:     total,
+	}, nil
+}
+
+// GetMemoryAsOf returns the memory as it was at the given time, rebuilt from
+// the latest revision recorded at or before it.
+func (r *MemoryRepo) GetMemoryAsOf(ctx context.Context, id string, asOf time.Time) (*memory.Memory, error) {
+	var (
+		memoryModel memory.Memory
+		date        sql.NullTime
+		created_at  sql.NullTime
+		updated_at  sql.NullTime
+		deleted_at  sql.NullTime
+		tags        []string
+	)
+	query := `
+		SELECT
+			s.id,
+			s.user_id,
+			s.title,
+			s.description,
+			s.date,
+			s.tags,
+			s.latitude,
+			s.longitude,
+			s.place_name,
+			s.privacy,
+			s.language::text,
+			s.created_at,
+			s.updated_at,
+			s.deleted_at,
+			s.version
+		FROM memory_revisions r,
+			jsonb_populate_record(NULL::memories, r.snapshot) s
+		WHERE r.memory_id = $1 AND r.created_at <= $2
+		ORDER BY r.created_at DESC, r.version DESC
+		LIMIT 1
 	`
 
-	result, err := r.db.Exec(ctx, query, id)
+	err := r.db.QueryRow(ctx, query, id, asOf).Scan(
+		&memoryModel.Id,
+		&memoryModel.UserId,
+		&memoryModel.Title,
+		&memoryModel.Description,
+		&date,
+		&tags,
+		&memoryModel.Latitude,
+		&memoryModel.Longitude,
+		&memoryModel.PlaceName,
+		&memoryModel.Privacy,
+		&memoryModel.Language,
+		&created_at,
+		&updated_at,
+		&deleted_at,
+		&memoryModel.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	memoryModel.Tags = tags
+	memoryModel.Date = helper.DateToString(date)
+	memoryModel.CreatedAt = helper.DateToString(created_at)
+	memoryModel.UpdatedAt = helper.DateToString(updated_at)
+	memoryModel.DeletedAt = helper.DateToString(deleted_at)
+	return &memoryModel, nil
+}
+
+// RestoreMemoryRevision writes the content of an earlier revision back to
+// the memory. The restore is itself recorded as a new revision, so nothing
+// in the history is lost.
+func (r *MemoryRepo) RestoreMemoryRevision(ctx context.Context, req *memory.RestoreMemoryRevisionRequest) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	if result.RowsAffected() == 0 {
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM memory_revisions WHERE id = $1 AND memory_id = $2)
+	`, req.RevisionId, req.MemoryId).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
 		return pgx.ErrNoRows
 	}
 
-	return nil
+	if err := setChangedBy(ctx, tx, req.ChangedBy); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE memories m
+		SET
+			title = s.title,
+			description = s.description,
+			date = s.date,
+			tags = s.tags,
+			latitude = s.latitude,
+			longitude = s.longitude,
+			place_name = s.place_name,
+			privacy = s.privacy,
+			language = s.language,
+			updated_at = NOW(),
+			version = m.version + 1
+		FROM memory_revisions r,
+			jsonb_populate_record(NULL::memories, r.snapshot) s
+		WHERE r.id = $1 AND m.id = r.memory_id
+			AND m.deleted_at IS NULL AND ($2::bigint = 0 OR m.version = $2)
+	`, req.RevisionId, req.ExpectedVersion)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return missingOrConflict(ctx, r.db, "memories", req.MemoryId, req.ExpectedVersion)
+	}
+
+	return tx.Commit(ctx)
 }
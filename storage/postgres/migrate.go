@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/time_capsule/memory-service/migrations"
+)
+
+// migrationLockID is the advisory lock key that serialises concurrent
+// migration runs (e.g. several replicas starting with AUTO_MIGRATE).
+const migrationLockID = 7267410035
+
+// MigrationStatus describes the schema version of the database.
+type MigrationStatus struct {
+	Version int64
+	Dirty   bool
+	Applied []migrations.Migration
+	Pending []migrations.Migration
+}
+
+// MigrateUp applies all pending migrations and returns how many were applied.
+func MigrateUp(ctx context.Context, db *pgxpool.Pool) (int, error) {
+	all, err := migrations.Load(migrations.FS)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	err = withMigrationLock(ctx, db, func(conn *pgxpool.Conn) error {
+		version, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range all {
+			if m.Version <= version {
+				continue
+			}
+			if err := runMigration(ctx, conn, m.Up, m.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", m.Version, m.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// MigrateDown rolls back the given number of applied migrations (newest
+// first) and returns how many were rolled back.
+func MigrateDown(ctx context.Context, db *pgxpool.Pool, steps int) (int, error) {
+	all, err := migrations.Load(migrations.FS)
+	if err != nil {
+		return 0, err
+	}
+
+	rolledBack := 0
+	err = withMigrationLock(ctx, db, func(conn *pgxpool.Conn) error {
+		version, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(all) - 1; i >= 0 && rolledBack < steps; i-- {
+			m := all[i]
+			if m.Version > version {
+				continue
+			}
+
+			var previous int64
+			if i > 0 {
+				previous = all[i-1].Version
+			}
+			if err := runMigration(ctx, conn, m.Down, previous); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", m.Version, m.Name, err)
+			}
+			rolledBack++
+		}
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// GetMigrationStatus reports the current schema version together with the
+// applied and pending migrations.
+func GetMigrationStatus(ctx context.Context, db *pgxpool.Pool) (*MigrationStatus, error) {
+	all, err := migrations.Load(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{}
+	err = db.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&status.Version, &status.Dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	for _, m := range all {
+		if m.Version <= status.Version {
+			status.Applied = append(status.Applied, m)
+		} else {
+			status.Pending = append(status.Pending, m)
+		}
+	}
+
+	return status, nil
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func ensureMigrationsTable(ctx context.Context, db execer) error {
+	// Same layout as golang-migrate, so the migrate CLI from the Makefile
+	// and the embedded runner agree on the current version.
+	_, err := db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty   BOOLEAN NOT NULL
+		)
+	`)
+	return err
+}
+
+func withMigrationLock(ctx context.Context, db *pgxpool.Pool, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func currentVersion(ctx context.Context, conn *pgxpool.Conn) (int64, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("database is dirty at version %d, fix it manually before migrating", version)
+	}
+	return version, nil
+}
+
+// runMigration executes script and records newVersion in a single
+// transaction, so a failed migration leaves the schema untouched.
+func runMigration(ctx context.Context, conn *pgxpool.Conn, script string, newVersion int64) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if newVersion > 0 {
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)`, newVersion); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
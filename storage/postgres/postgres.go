@@ -4,22 +4,69 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/time_capsule/memory-service/config"
 	"github.com/time_capsule/memory-service/storage"
 )
 
 // Storage implements the storage.StorageI interface for PostgreSQL.
 type Storage struct {
-	db       *pgx.Conn
+	db       querier
 	MemoryS  storage.MemoryI
 	MediaS   storage.MediaI
 	CommentS storage.CommentI
+	CommandS storage.CommandI
 }
 
 // NewPostgresStorage creates a new PostgreSQL storage instance.
 func NewPostgresStorage(cfg config.Config) (storage.StorageI, error) {
+	db, err := NewPool(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.AutoMigrate {
+		applied, err := MigrateUp(context.Background(), db)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+		slog.Info("Database migrated", "applied", applied)
+	}
+
+	return NewStorage(db), nil
+}
+
+// NewStorage creates a PostgreSQL storage instance on top of an existing
+// pool or transaction.
+func NewStorage(db querier) *Storage {
+	return &Storage{
+		db:       db,
+		MemoryS:  classifier.Memory(NewMemoryRepo(db)),
+		MediaS:   classifier.Media(NewMediaRepo(db)),
+		CommentS: classifier.Comment(NewCommentRepo(db)),
+		CommandS: classifier.Command(NewCommandRepo(db)),
+	}
+}
+
+// querier is implemented by both *pgxpool.Pool and pgx.Tx. Begin on a
+// pgx.Tx starts a savepoint, so repositories can open their own
+// transactions inside WithTx.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// NewPool creates a PostgreSQL connection pool from the configuration.
+// The pool is safe for concurrent use; broken connections are discarded and
+// replaced transparently, so the service recovers once the database is back.
+func NewPool(ctx context.Context, cfg config.Config) (*pgxpool.Pool, error) {
 	dbCon := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s",
 		cfg.PostgresUser,
 		cfg.PostgresPassword,
@@ -28,23 +75,64 @@ func NewPostgresStorage(cfg config.Config) (storage.StorageI, error) {
 		cfg.PostgresDB,
 	)
 
-	db, err := pgx.Connect(context.Background(), dbCon)
+	poolCfg, err := pgxpool.ParseConfig(dbCon)
 	if err != nil {
-		slog.Warn("Unable to connect to database:", err)
-		return nil, err
+		return nil, fmt.Errorf("invalid database config: %w", err)
 	}
 
-	if err := db.Ping(context.Background()); err != nil {
-		slog.Warn("Unable to ping database:", err)
+	if cfg.PostgresMaxConns > 0 {
+		poolCfg.MaxConns = cfg.PostgresMaxConns
+	}
+	if cfg.PostgresMinConns > 0 {
+		poolCfg.MinConns = cfg.PostgresMinConns
+	}
+	if cfg.PostgresMaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.PostgresMaxConnLifetime
+	}
+	if cfg.PostgresMaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.PostgresMaxConnIdleTime
+	}
+	if cfg.PostgresHealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.PostgresHealthCheckPeriod
+	}
+	if cfg.PostgresConnectTimeout > 0 {
+		poolCfg.ConnConfig.ConnectTimeout = cfg.PostgresConnectTimeout
+	}
+
+	db, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		slog.Warn("Unable to create connection pool", "err", err)
 		return nil, err
 	}
 
-	return &Storage{
-		db:       db,
-		MemoryS:  NewMemoryRepo(db),
-		MediaS:   NewMediaRepo(db),
-		CommentS: NewCommentRepo(db),
-	}, nil
+	// The database may still be starting up (e.g. under docker-compose), so
+	// retry the initial ping with a growing delay before giving up.
+	attempts := cfg.PostgresConnectAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err = db.Ping(ctx)
+		if err == nil {
+			break
+		}
+		if attempt >= attempts {
+			slog.Warn("Unable to ping database", "err", err)
+			db.Close()
+			return nil, err
+		}
+		slog.Warn("Database not ready, retrying", "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			db.Close()
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return db, nil
 }
 
 // Memory returns the MemoryI implementation for PostgreSQL.
@@ -61,3 +149,109 @@ func (s *Storage) Media() storage.MediaI {
 func (s *Storage) Comment() storage.CommentI {
 	return s.CommentS
 }
+
+// Command returns the CommandI implementation for PostgreSQL.
+func (s *Storage) Command() storage.CommandI {
+	return s.CommandS
+}
+
+// WithTx runs fn with repositories bound to a single transaction, which is
+// committed if fn returns nil and rolled back if it fails or panics. Called
+// on a transactional storage it runs fn in a savepoint of the enclosing
+// transaction.
+func (s *Storage) WithTx(ctx context.Context, fn func(tx storage.StorageI) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return classifier.Classify(err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(NewStorage(tx)); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	return classifier.Classify(tx.Commit(ctx))
+}
+
+// missingOrConflict explains why a versioned write to table matched no rows:
+// either the row is gone (pgx.ErrNoRows) or it has moved past the expected
+// version (storage.ErrVersionConflict).
+func missingOrConflict(ctx context.Context, db querier, table, id string, expectedVersion int64) error {
+	if expectedVersion == 0 {
+		return pgx.ErrNoRows
+	}
+
+	var version int64
+	err := db.QueryRow(ctx, `SELECT version FROM `+table+` WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s %s is at version %d, expected %d", storage.ErrVersionConflict, table, id, version, expectedVersion)
+}
+
+// setChangedBy names the user behind the writes of the current transaction,
+// so the memory revision trigger can attribute them. An empty user leaves
+// the attribution to the memory owner.
+func setChangedBy(ctx context.Context, tx pgx.Tx, userID string) error {
+	_, err := tx.Exec(ctx, `SELECT set_config('memory_service.changed_by', $1, true)`, userID)
+	return err
+}
+
+// optionalTime returns nil for the zero time, which update statements use
+// to keep the stored value.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// bulkRows bounds the rows of a multi-row statement, keeping its parameters
+// well below the 65535 PostgreSQL accepts.
+const bulkRows = 1000
+
+// rowParams returns the parameter numbers of row (counting from 0) of a
+// multi-row statement whose rows take count parameters each, for formatting
+// the row with fmt.Sprintf.
+func rowParams(row, count int) []interface{} {
+	params := make([]interface{}, count)
+	for i := range params {
+		params[i] = row*count + i + 1
+	}
+	return params
+}
+
+// insertForMemories runs a multi-row insert of media or comments that
+// returns the memory_id of every inserted row. Rows whose memory is missing
+// or trashed are not inserted; the first such memory is reported as
+// storage.ErrMemoryNotFound.
+func insertForMemories(ctx context.Context, db querier, query string, memoryIDs []string, args []interface{}) error {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	inserted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	if len(inserted) == len(memoryIDs) {
+		return nil
+	}
+
+	found := make(map[string]bool, len(inserted))
+	for _, id := range inserted {
+		found[id] = true
+	}
+	for _, id := range memoryIDs {
+		if !found[id] {
+			return fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, id)
+		}
+	}
+	return nil
+}
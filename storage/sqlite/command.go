@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+)
+
+type CommandRepo struct {
+	db querier
+}
+
+func NewCommandRepo(db querier) *CommandRepo {
+	return &CommandRepo{
+		db: db,
+	}
+}
+
+// MarkCommandProcessed records a processed command. The primary key rejects
+// a command that was recorded before.
+func (r *CommandRepo) MarkCommandProcessed(ctx context.Context, id, operation string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO processed_commands (id, operation, processed_at) VALUES (?, ?, ?)
+	`, id, operation, encodeTime(now()))
+	return err
+}
+
+// PurgeCommands forgets the commands processed before the given time.
+func (r *CommandRepo) PurgeCommands(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM processed_commands
+		WHERE processed_at < ?
+	`, encodeTime(before))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
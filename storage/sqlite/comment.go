@@ -0,0 +1,416 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+const commentColumns = `id, memory_id, user_id, content, created_at, updated_at, deleted_at, version`
+
+type CommentRepo struct {
+	db querier
+}
+
+func NewCommentRepo(db querier) *CommentRepo {
+	return &CommentRepo{
+		db: db,
+	}
+}
+
+// scanComment reads a row selected with commentColumns.
+func scanComment(row interface{ Scan(...interface{}) error }) (*memory.Comment, string, error) {
+	var (
+		commentModel memory.Comment
+		createdAt    sql.NullString
+		updatedAt    sql.NullString
+		deletedAt    sql.NullString
+	)
+	err := row.Scan(
+		&commentModel.Id,
+		&commentModel.MemoryId,
+		&commentModel.UserId,
+		&commentModel.Content,
+		&createdAt,
+		&updatedAt,
+		&deletedAt,
+		&commentModel.Version,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	commentModel.CreatedAt = formatTime(createdAt)
+	commentModel.UpdatedAt = formatTime(updatedAt)
+	commentModel.DeletedAt = formatTime(deletedAt)
+	return &commentModel, createdAt.String, nil
+}
+
+func (r *CommentRepo) CreateComment(ctx context.Context, comment *models.CreateCommentModel) (string, error) {
+	if comment.ID == "" {
+		comment.ID = uuid.NewString()
+	}
+	query := `
+		INSERT INTO comments (
+			id,
+			memory_id,
+			user_id,
+			content,
+			created_at,
+			updated_at
+		)
+		SELECT ?, ?, ?, ?, ?, ?
+		WHERE EXISTS (
+			SELECT 1 FROM memories WHERE id = ? AND deleted_at IS NULL
+		)
+	`
+
+	ts := encodeTime(now())
+	result, err := r.db.ExecContext(ctx, query,
+		comment.ID,
+		comment.MemoryID,
+		comment.UserID,
+		comment.Content,
+		ts,
+		ts,
+		comment.MemoryID,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return "", err
+	} else if n == 0 {
+		return "", fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, comment.MemoryID)
+	}
+
+	return comment.ID, nil
+}
+
+// BulkCreateComments creates the comments in a single transaction, so
+// either all are created or none.
+func (r *CommentRepo) BulkCreateComments(ctx context.Context, comments []*models.CreateCommentModel) ([]string, error) {
+	ids := make([]string, 0, len(comments))
+	err := inTx(ctx, r.db, func(tx querier) error {
+		repo := NewCommentRepo(tx)
+		for _, m := range comments {
+			id, err := repo.CreateComment(ctx, m)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *CommentRepo) GetCommentByID(ctx context.Context, id string) (*memory.Comment, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+commentColumns+` FROM comments WHERE id = ? AND deleted_at IS NULL`, id)
+	commentModel, _, err := scanComment(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return commentModel, nil
+}
+
+func (r *CommentRepo) GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	var args []interface{}
+	filter := ""
+
+	if req.MemoryId != "" {
+		filter += " AND memory_id = ?"
+		args = append(args, req.MemoryId)
+	}
+
+	if req.UserId != "" {
+		filter += " AND user_id = ?"
+		args = append(args, req.UserId)
+	}
+
+	if req.Content != "" {
+		filter += " AND content LIKE ?"
+		args = append(args, "%"+req.Content+"%")
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM comments WHERE deleted_at IS NULL` + filter
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last row of the previous
+		// page, so rows inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		filter += " AND (created_at, id) < (?, ?)"
+		args = append(args, encodeTime(cursor.Time), cursor.ID)
+		offset = 0
+	}
+
+	// One extra row tells us whether there is a next page.
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE deleted_at IS NULL` + filter +
+		` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit+1, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		commentList   []*memory.Comment
+		lastCreatedAt string
+		nextPageToken string
+	)
+
+	for rows.Next() {
+		commentModel, createdAt, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		if int32(len(commentList)) == limit {
+			last, err := decodeTime(lastCreatedAt)
+			if err != nil {
+				return nil, err
+			}
+			nextPageToken = helper.EncodeCursor(last, commentList[len(commentList)-1].Id)
+			break
+		}
+		commentList = append(commentList, commentModel)
+		lastCreatedAt = createdAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllCommentsResponse{
+		Comments:      commentList,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (r *CommentRepo) UpdateComment(ctx context.Context, comment *models.UpdateCommentModel) error {
+	if err := ensureMemoryExists(ctx, r.db, comment.MemoryID); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE comments
+		SET
+			memory_id = ?,
+			user_id = ?,
+			content = ?,
+			created_at = COALESCE(?, created_at),
+			updated_at = ?,
+			version = version + 1
+		WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		comment.MemoryID,
+		comment.UserID,
+		comment.Content,
+		encodeOptionalTime(comment.Created),
+		encodeTime(now()),
+		comment.ID,
+		comment.ExpectedVersion,
+		comment.ExpectedVersion,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := expectAffected(result); err != nil {
+		if err == sql.ErrNoRows {
+			return missingOrConflict(ctx, r.db, "comments", comment.ID, comment.ExpectedVersion)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *CommentRepo) PatchComment(ctx context.Context, comment *models.PatchCommentModel) error {
+	if comment.MemoryID != nil {
+		if err := ensureMemoryExists(ctx, r.db, *comment.MemoryID); err != nil {
+			return err
+		}
+	}
+
+	var args []interface{}
+	query := `
+		UPDATE comments
+		SET
+	`
+
+	filter := ""
+
+	if comment.MemoryID != nil {
+		filter += " memory_id = ?, "
+		args = append(args, *comment.MemoryID)
+	}
+
+	if comment.UserID != nil {
+		filter += " user_id = ?, "
+		args = append(args, *comment.UserID)
+	}
+
+	if comment.Content != nil {
+		filter += " content = ?, "
+		args = append(args, *comment.Content)
+	}
+
+	if comment.Created != nil {
+		filter += " created_at = ?, "
+		args = append(args, encodeTime(*comment.Created))
+	}
+
+	if filter == "" {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	// Every write bumps the version; a non-zero expected version must match.
+	filter += " updated_at = ?, version = version + 1"
+	args = append(args, encodeTime(now()))
+	query += filter + " WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)"
+	args = append(args, comment.ID, comment.ExpectedVersion, comment.ExpectedVersion)
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if err := expectAffected(result); err != nil {
+		if err == sql.ErrNoRows {
+			return missingOrConflict(ctx, r.db, "comments", comment.ID, comment.ExpectedVersion)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteComment moves a comment to the trash.
+func (r *CommentRepo) DeleteComment(ctx context.Context, id string) error {
+	ts := encodeTime(now())
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE comments
+		SET deleted_at = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND deleted_at IS NULL
+	`, ts, ts, id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+func (r *CommentRepo) GetDeletedComments(ctx context.Context, req *memory.GetDeletedCommentsRequest) (*memory.GetAllCommentsResponse, error) {
+	var args []interface{}
+	filter := ""
+
+	if req.MemoryId != "" {
+		filter += " AND memory_id = ?"
+		args = append(args, req.MemoryId)
+	}
+
+	if req.UserId != "" {
+		filter += " AND user_id = ?"
+		args = append(args, req.UserId)
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM comments WHERE deleted_at IS NOT NULL` + filter
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE deleted_at IS NOT NULL` + filter +
+		` ORDER BY deleted_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commentList []*memory.Comment
+	for rows.Next() {
+		commentModel, _, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		commentList = append(commentList, commentModel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllCommentsResponse{
+		Comments: commentList,
+		Count:    total,
+	}, nil
+}
+
+// RestoreComment takes a comment out of the trash. A comment whose memory is
+// still in the trash cannot be restored on its own.
+func (r *CommentRepo) RestoreComment(ctx context.Context, id string) error {
+	var memoryDeleted bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT m.deleted_at IS NOT NULL
+		FROM comments c
+		JOIN memories m ON m.id = c.memory_id
+		WHERE c.id = ? AND c.deleted_at IS NOT NULL
+	`, id).Scan(&memoryDeleted)
+	if err != nil {
+		return err
+	}
+	if memoryDeleted {
+		return storage.Errorf(storage.ErrConflict, "cannot restore comment %s: its memory is in the trash", id)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE comments
+		SET deleted_at = NULL, updated_at = ?, version = version + 1
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`, encodeTime(now()), id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+// PurgeComments permanently deletes comments that were trashed before the given time.
+func (r *CommentRepo) PurgeComments(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM comments
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+	`, encodeTime(before))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
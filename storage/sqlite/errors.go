@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/time_capsule/memory-service/storage"
+	driver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// classifier maps SQLite errors onto the storage error kinds.
+var classifier = storage.Classifier(errorKind)
+
+func errorKind(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrNotFound
+	}
+	if errors.Is(err, sql.ErrConnDone) {
+		return storage.ErrUnavailable
+	}
+
+	var sqliteErr *driver.Error
+	if !errors.As(err, &sqliteErr) {
+		return nil
+	}
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		return storage.ErrAlreadyExists
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return storage.ErrNotFound
+	case sqlite3.SQLITE_CONSTRAINT_NOTNULL, sqlite3.SQLITE_CONSTRAINT_CHECK:
+		return storage.ErrInvalidArgument
+	}
+	// Extended result codes carry the primary code in the low byte.
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED, sqlite3.SQLITE_CANTOPEN,
+		sqlite3.SQLITE_IOERR, sqlite3.SQLITE_FULL:
+		return storage.ErrUnavailable
+	}
+	return nil
+}
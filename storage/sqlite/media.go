@@ -0,0 +1,389 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+const mediaColumns = `id, memory_id, type, url, created_at, deleted_at`
+
+type MediaRepo struct {
+	db querier
+}
+
+func NewMediaRepo(db querier) *MediaRepo {
+	return &MediaRepo{
+		db: db,
+	}
+}
+
+// scanMedia reads a row selected with mediaColumns.
+func scanMedia(row interface{ Scan(...interface{}) error }) (*memory.Media, string, error) {
+	var (
+		mediaModel memory.Media
+		createdAt  sql.NullString
+		deletedAt  sql.NullString
+	)
+	err := row.Scan(
+		&mediaModel.Id,
+		&mediaModel.MemoryId,
+		&mediaModel.Type,
+		&mediaModel.Url,
+		&createdAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	mediaModel.CreatedAt = formatTime(createdAt)
+	mediaModel.DeletedAt = formatTime(deletedAt)
+	return &mediaModel, createdAt.String, nil
+}
+
+func (r *MediaRepo) CreateMedia(ctx context.Context, media *models.CreateMediaModel) (string, error) {
+	if media.ID == "" {
+		media.ID = uuid.NewString()
+	}
+	query := `
+		INSERT INTO media (
+			id,
+			memory_id,
+			type,
+			url,
+			created_at
+		)
+		SELECT ?, ?, ?, ?, ?
+		WHERE EXISTS (
+			SELECT 1 FROM memories WHERE id = ? AND deleted_at IS NULL
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		media.ID,
+		media.MemoryID,
+		media.Type,
+		media.URL,
+		encodeTime(now()),
+		media.MemoryID,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return "", err
+	} else if n == 0 {
+		return "", fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, media.MemoryID)
+	}
+
+	return media.ID, nil
+}
+
+// BulkCreateMedia creates the media in a single transaction, so
+// either all are created or none.
+func (r *MediaRepo) BulkCreateMedia(ctx context.Context, media []*models.CreateMediaModel) ([]string, error) {
+	ids := make([]string, 0, len(media))
+	err := inTx(ctx, r.db, func(tx querier) error {
+		repo := NewMediaRepo(tx)
+		for _, m := range media {
+			id, err := repo.CreateMedia(ctx, m)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *MediaRepo) GetMediaByID(ctx context.Context, id string) (*memory.Media, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+mediaColumns+` FROM media WHERE id = ? AND deleted_at IS NULL`, id)
+	mediaModel, _, err := scanMedia(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return mediaModel, nil
+}
+
+func (r *MediaRepo) GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) (*memory.GetAllMediaResponse, error) {
+	var args []interface{}
+	filter := ""
+
+	if req.MemoryId != "" {
+		filter += " AND memory_id = ?"
+		args = append(args, req.MemoryId)
+	}
+
+	if len(req.Type) > 0 {
+		filter += " AND type LIKE ?"
+		args = append(args, "%"+req.Type+"%")
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM media WHERE deleted_at IS NULL` + filter
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last row of the previous
+		// page, so rows inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		filter += " AND (created_at, id) < (?, ?)"
+		args = append(args, encodeTime(cursor.Time), cursor.ID)
+		offset = 0
+	}
+
+	// One extra row tells us whether there is a next page.
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE deleted_at IS NULL` + filter +
+		` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit+1, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		mediaList     []*memory.Media
+		lastCreatedAt string
+		nextPageToken string
+	)
+
+	for rows.Next() {
+		mediaModel, createdAt, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		if int32(len(mediaList)) == limit {
+			last, err := decodeTime(lastCreatedAt)
+			if err != nil {
+				return nil, err
+			}
+			nextPageToken = helper.EncodeCursor(last, mediaList[len(mediaList)-1].Id)
+			break
+		}
+		mediaList = append(mediaList, mediaModel)
+		lastCreatedAt = createdAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMediaResponse{
+		Media:         mediaList,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (r *MediaRepo) UpdateMedia(ctx context.Context, media *models.UpdateMediaModel) error {
+	if err := ensureMemoryExists(ctx, r.db, media.MemoryID); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE media
+		SET
+			memory_id = ?,
+			type = ?,
+			url = ?,
+			created_at = COALESCE(?, created_at)
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		media.MemoryID,
+		media.Type,
+		media.URL,
+		encodeOptionalTime(media.Created),
+		media.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+func (r *MediaRepo) PatchMedia(ctx context.Context, media *models.PatchMediaModel) error {
+	if media.MemoryID != nil {
+		if err := ensureMemoryExists(ctx, r.db, *media.MemoryID); err != nil {
+			return err
+		}
+	}
+
+	var args []interface{}
+	query := `
+		UPDATE media
+		SET
+	`
+
+	filter := ""
+
+	if media.MemoryID != nil {
+		filter += " memory_id = ?, "
+		args = append(args, *media.MemoryID)
+	}
+
+	if media.Type != nil {
+		filter += " type = ?, "
+		args = append(args, *media.Type)
+	}
+
+	if media.URL != nil {
+		filter += " url = ?, "
+		args = append(args, *media.URL)
+	}
+
+	if media.Created != nil {
+		filter += " created_at = ?, "
+		args = append(args, encodeTime(*media.Created))
+	}
+
+	if filter == "" {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	filter = filter[:len(filter)-2] // Remove the trailing comma and space
+	query += filter + " WHERE id = ? AND deleted_at IS NULL"
+	args = append(args, media.ID)
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+// DeleteMedia moves media to the trash.
+func (r *MediaRepo) DeleteMedia(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE media
+		SET deleted_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`, encodeTime(now()), id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+func (r *MediaRepo) GetDeletedMedia(ctx context.Context, req *memory.GetDeletedMediaRequest) (*memory.GetAllMediaResponse, error) {
+	var args []interface{}
+	filter := ""
+
+	if req.MemoryId != "" {
+		filter += " AND memory_id = ?"
+		args = append(args, req.MemoryId)
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM media WHERE deleted_at IS NOT NULL` + filter
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE deleted_at IS NOT NULL` + filter +
+		` ORDER BY deleted_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mediaList []*memory.Media
+	for rows.Next() {
+		mediaModel, _, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		mediaList = append(mediaList, mediaModel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMediaResponse{
+		Media: mediaList,
+		Count: total,
+	}, nil
+}
+
+// RestoreMedia takes media out of the trash. Media whose memory is still
+// in the trash cannot be restored on its own.
+func (r *MediaRepo) RestoreMedia(ctx context.Context, id string) error {
+	var memoryDeleted bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT m.deleted_at IS NOT NULL
+		FROM media md
+		JOIN memories m ON m.id = md.memory_id
+		WHERE md.id = ? AND md.deleted_at IS NOT NULL
+	`, id).Scan(&memoryDeleted)
+	if err != nil {
+		return err
+	}
+	if memoryDeleted {
+		return storage.Errorf(storage.ErrConflict, "cannot restore media %s: its memory is in the trash", id)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE media
+		SET deleted_at = NULL
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result)
+}
+
+// PurgeMedia permanently deletes media that were trashed before the given time.
+func (r *MediaRepo) PurgeMedia(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM media
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+	`, encodeTime(before))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// expectAffected returns sql.ErrNoRows if a write matched no rows.
+func expectAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
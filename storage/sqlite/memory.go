@@ -0,0 +1,1143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// defaultSearchLanguage is recorded for memories that do not specify a
+// language. The full-text index stems english words regardless of it.
+const defaultSearchLanguage = "english"
+
+// memoryColumns selects a memory from the table aliased as m, in the order
+// scanMemory expects.
+const memoryColumns = `m.id, m.user_id, m.title, m.description, m.date, m.tags, m.latitude, m.longitude,
+	m.place_name, m.privacy, m.language, m.created_at, m.updated_at, m.deleted_at, m.version`
+
+// memoryRow is a decoded memories row. It doubles as the revision snapshot.
+type memoryRow struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Date        time.Time  `json:"date"`
+	Tags        []string   `json:"tags"`
+	Latitude    *float64   `json:"latitude"`
+	Longitude   *float64   `json:"longitude"`
+	PlaceName   string     `json:"place_name"`
+	Privacy     string     `json:"privacy"`
+	Language    string     `json:"language"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+	Version     int64      `json:"version"`
+}
+
+// scanMemory reads a row selected with memoryColumns, followed by extra
+// columns scanned into dest.
+func scanMemory(row interface{ Scan(...interface{}) error }, dest ...interface{}) (*memoryRow, error) {
+	var (
+		m         memoryRow
+		date      string
+		tags      string
+		createdAt string
+		updatedAt string
+		deletedAt sql.NullString
+	)
+	err := row.Scan(append([]interface{}{
+		&m.ID,
+		&m.UserID,
+		&m.Title,
+		&m.Description,
+		&date,
+		&tags,
+		&m.Latitude,
+		&m.Longitude,
+		&m.PlaceName,
+		&m.Privacy,
+		&m.Language,
+		&createdAt,
+		&updatedAt,
+		&deletedAt,
+		&m.Version,
+	}, dest...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Date, err = decodeTime(date); err != nil {
+		return nil, err
+	}
+	if m.CreatedAt, err = decodeTime(createdAt); err != nil {
+		return nil, err
+	}
+	if m.UpdatedAt, err = decodeTime(updatedAt); err != nil {
+		return nil, err
+	}
+	if m.DeletedAt, err = decodeNullTime(deletedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(tags), &m.Tags); err != nil {
+		return nil, fmt.Errorf("invalid tags of memory %s: %w", m.ID, err)
+	}
+	return &m, nil
+}
+
+func (m *memoryRow) toProto() *memory.Memory {
+	deletedAt := ""
+	if m.DeletedAt != nil {
+		deletedAt = m.DeletedAt.Format(time.RFC3339)
+	}
+	return &memory.Memory{
+		Id:          m.ID,
+		UserId:      m.UserID,
+		Title:       m.Title,
+		Description: m.Description,
+		Date:        m.Date.Format(time.RFC3339),
+		Tags:        m.Tags,
+		Latitude:    m.Latitude,
+		Longitude:   m.Longitude,
+		PlaceName:   m.PlaceName,
+		Privacy:     m.Privacy,
+		Language:    m.Language,
+		CreatedAt:   m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   m.UpdatedAt.Format(time.RFC3339),
+		DeletedAt:   deletedAt,
+		Version:     m.Version,
+	}
+}
+
+// clone returns a copy of m that can be changed without touching m.
+func (m *memoryRow) clone() *memoryRow {
+	c := *m
+	c.Tags = append([]string(nil), m.Tags...)
+	return &c
+}
+
+// encodeTags returns the stored JSON form of tags. A nil slice is stored as
+// an empty array.
+func encodeTags(tags []string) string {
+	if tags == nil {
+		tags = []string{}
+	}
+	b, _ := json.Marshal(tags)
+	return string(b)
+}
+
+type MemoryRepo struct {
+	db querier
+}
+
+func NewMemoryRepo(db querier) *MemoryRepo {
+	return &MemoryRepo{
+		db: db,
+	}
+}
+
+func (r *MemoryRepo) CreateMemory(ctx context.Context, memory *models.CreateMemoryModel) (string, error) {
+	if memory.ID == "" {
+		memory.ID = uuid.NewString()
+	}
+	language := memory.Language
+	if language == "" {
+		language = defaultSearchLanguage
+	}
+
+	ts := now()
+	m := &memoryRow{
+		ID:          memory.ID,
+		UserID:      memory.UserID,
+		Title:       memory.Title,
+		Description: memory.Description,
+		Date:        memory.Date.UTC().Truncate(time.Microsecond),
+		Tags:        memory.Tags,
+		Latitude:    memory.Latitude,
+		Longitude:   memory.Longitude,
+		PlaceName:   memory.PlaceName,
+		Privacy:     memory.Privacy,
+		Language:    language,
+		CreatedAt:   ts,
+		UpdatedAt:   ts,
+		Version:     1,
+	}
+
+	query := `
+		INSERT INTO memories (
+			id,
+			user_id,
+			title,
+			description,
+			date,
+			tags,
+			latitude,
+			longitude,
+			place_name,
+			privacy,
+			language,
+			created_at,
+			updated_at,
+			version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	err := inTx(ctx, r.db, func(tx querier) error {
+		_, err := tx.ExecContext(ctx, query,
+			m.ID,
+			m.UserID,
+			m.Title,
+			m.Description,
+			encodeTime(m.Date),
+			encodeTags(m.Tags),
+			m.Latitude,
+			m.Longitude,
+			m.PlaceName,
+			m.Privacy,
+			m.Language,
+			encodeTime(m.CreatedAt),
+			encodeTime(m.UpdatedAt),
+			m.Version,
+		)
+		if err != nil {
+			return err
+		}
+
+		return recordRevision(ctx, tx, nil, m, "")
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return memory.ID, nil
+}
+
+// BulkCreateMemories creates the memories in a single transaction, so
+// either all are created or none.
+func (r *MemoryRepo) BulkCreateMemories(ctx context.Context, memories []*models.CreateMemoryModel) ([]string, error) {
+	ids := make([]string, 0, len(memories))
+	err := inTx(ctx, r.db, func(tx querier) error {
+		repo := NewMemoryRepo(tx)
+		for _, m := range memories {
+			id, err := repo.CreateMemory(ctx, m)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *MemoryRepo) GetMemoryByID(ctx context.Context, id string) (*memory.Memory, error) {
+	m, err := getMemory(ctx, r.db, `m.id = ? AND m.deleted_at IS NULL`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.toProto(), nil
+}
+
+// getMemory returns the memory matching the where condition.
+func getMemory(ctx context.Context, db querier, where string, args ...interface{}) (*memoryRow, error) {
+	row := db.QueryRowContext(ctx, `SELECT `+memoryColumns+` FROM memories m WHERE `+where, args...)
+	return scanMemory(row)
+}
+
+func (r *MemoryRepo) GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	cond, err := memoryFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	filter, args := cond.filter, cond.args
+
+	// Relevance and distance orderings are not keyset-friendly, so they are
+	// paginated with page/limit only.
+	orderBy := " ORDER BY m.date DESC, m.id DESC"
+	keyset := true
+	switch req.SortBy {
+	case "", "date":
+	case "relevance":
+		if !cond.search {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "sort by relevance requires a search term")
+		}
+		orderBy = " ORDER BY search_rank DESC, m.date DESC, m.id DESC"
+		keyset = false
+	case "distance":
+		if cond.center == nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "sort by distance requires a center point")
+		}
+		orderBy = " ORDER BY distance_meters ASC, m.id DESC"
+		keyset = false
+	default:
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid sort_by %q", req.SortBy)
+	}
+	if !keyset && req.PageToken != "" {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "page tokens are only supported when sorting by date")
+	}
+
+	from := ` FROM memories m`
+	if cond.search {
+		from += ` JOIN memories_fts ON memories_fts.memory_id = m.id`
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*)` + from + ` WHERE m.deleted_at IS NULL` + filter
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	if req.PageToken != "" {
+		// Keyset seek: continue strictly after the last row of the previous
+		// page, so rows inserted meanwhile never shift the window.
+		cursor, err := helper.DecodeCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		filter += " AND (m.date, m.id) < (?, ?)"
+		args = append(args, encodeTime(cursor.Time), cursor.ID)
+		offset = 0
+	}
+
+	// Rank and highlight only when searching; otherwise return neutral
+	// values. bm25 is lower for better matches, so it is negated.
+	searchColumns := `0.0 AS search_rank, '' AS highlight`
+	if cond.search {
+		searchColumns = `-bm25(memories_fts, 0, 8, 4, 2, 1) AS search_rank,
+			highlight(memories_fts, 1, '<b>', '</b>') || ' ' || highlight(memories_fts, 3, '<b>', '</b>') AS highlight`
+	}
+	var selectArgs []interface{}
+	distanceColumn := `0.0 AS distance_meters`
+	if cond.center != nil {
+		distanceColumn = distanceExpr + ` AS distance_meters`
+		selectArgs = distanceArgs(cond.center[0], cond.center[1])
+	}
+
+	// One extra row tells us whether there is a next page.
+	query := `SELECT ` + memoryColumns + `, ` + searchColumns + `, ` + distanceColumn +
+		from + ` WHERE m.deleted_at IS NULL` + filter + orderBy + ` LIMIT ? OFFSET ?`
+	args = append(append(selectArgs, args...), limit+1, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		memories      []*memory.Memory
+		lastDate      time.Time
+		nextPageToken string
+	)
+
+	for rows.Next() {
+		var (
+			searchRank     float64
+			highlight      string
+			distanceMeters float64
+		)
+		m, err := scanMemory(rows, &searchRank, &highlight, &distanceMeters)
+		if err != nil {
+			return nil, err
+		}
+		if int32(len(memories)) == limit {
+			if keyset {
+				nextPageToken = helper.EncodeCursor(lastDate, memories[len(memories)-1].Id)
+			}
+			break
+		}
+		memoryModel := m.toProto()
+		memoryModel.SearchRank = searchRank
+		memoryModel.Highlight = highlight
+		memoryModel.DistanceMeters = distanceMeters
+		memories = append(memories, memoryModel)
+		lastDate = m.Date
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMemoriesResponse{
+		Memories:      memories,
+		Count:         total,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// memoryConditions holds the WHERE conditions of a GetAllMemories request
+// along with what is needed to rank and measure the matches.
+type memoryConditions struct {
+	filter string
+	args   []interface{}
+	search bool        // set when searching, joins the full-text index
+	center *[2]float64 // latitude/longitude of the requested center point
+}
+
+// memoryFilter builds the WHERE conditions shared by the list and count
+// queries of GetAllMemories. It mirrors the Postgres filter semantics.
+func memoryFilter(req *memory.GetAllMemoriesRequest) (*memoryConditions, error) {
+	var args []interface{}
+	filter := ""
+	cond := &memoryConditions{}
+
+	if req.SearchTerm != "" {
+		cond.search = true
+		filter += " AND memories_fts MATCH ?"
+		args = append(args, matchQuery(req.SearchTerm))
+	}
+
+	if req.UserId != "" {
+		filter += " AND m.user_id = ?"
+		args = append(args, req.UserId)
+	}
+
+	if req.Title != "" {
+		filter += " AND m.title LIKE ?"
+		args = append(args, "%"+req.Title+"%")
+	}
+
+	if req.Description != "" {
+		filter += " AND m.description LIKE ?"
+		args = append(args, "%"+req.Description+"%")
+	}
+
+	if len(req.Tags) > 0 {
+		// Any of the tags, like the && array operator in Postgres.
+		filter += " AND EXISTS (SELECT 1 FROM json_each(m.tags) WHERE json_each.value IN (?" +
+			strings.Repeat(", ?", len(req.Tags)-1) + "))"
+		for _, tag := range req.Tags {
+			args = append(args, tag)
+		}
+	}
+
+	if req.StartDate != "" {
+		startTime, err := time.Parse(time.RFC3339, req.StartDate)
+		if err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid start time format: %w", err)
+		}
+		filter += " AND m.date >= ?"
+		args = append(args, encodeTime(startTime))
+	}
+
+	if req.EndDate != "" {
+		endTime, err := time.Parse(time.RFC3339, req.EndDate)
+		if err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid end time format: %w", err)
+		}
+		filter += " AND m.date <= ?"
+		args = append(args, encodeTime(endTime))
+	}
+
+	if req.Latitude != nil {
+		filter += " AND m.latitude = ?"
+		args = append(args, *req.Latitude)
+	}
+
+	if req.Longitude != nil {
+		filter += " AND m.longitude = ?"
+		args = append(args, *req.Longitude)
+	}
+
+	if (req.CenterLatitude == nil) != (req.CenterLongitude == nil) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "center_latitude and center_longitude must be set together")
+	}
+	if req.RadiusMeters < 0 {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "radius_meters must not be negative")
+	}
+	if req.RadiusMeters > 0 && req.CenterLatitude == nil {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "radius_meters requires center_latitude and center_longitude")
+	}
+	if req.CenterLatitude != nil {
+		lat, lon := *req.CenterLatitude, *req.CenterLongitude
+		if err := helper.ValidateCoordinates(lat, lon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid center point: %w", err)
+		}
+
+		cond.center = &[2]float64{lat, lon}
+
+		filter += " AND m.latitude IS NOT NULL AND m.longitude IS NOT NULL"
+		if req.RadiusMeters > 0 {
+			// Cheap bounding-box pre-filter (index friendly) before the exact distance check.
+			minLat, minLon, maxLat, maxLon, ok := helper.BoundingBox(lat, lon, req.RadiusMeters)
+			filter += " AND m.latitude BETWEEN ? AND ?"
+			args = append(args, minLat, maxLat)
+			if ok {
+				filter += longitudeRange(minLon, maxLon)
+				args = append(args, minLon, maxLon)
+			}
+
+			filter += " AND " + distanceExpr + " <= ?"
+			args = append(append(args, distanceArgs(lat, lon)...), req.RadiusMeters)
+		}
+	}
+
+	bbox := []*float64{req.MinLatitude, req.MinLongitude, req.MaxLatitude, req.MaxLongitude}
+	bboxSet := 0
+	for _, v := range bbox {
+		if v != nil {
+			bboxSet++
+		}
+	}
+	if bboxSet != 0 && bboxSet != len(bbox) {
+		return nil, storage.Errorf(storage.ErrInvalidArgument, "bounding box requires min/max latitude and longitude")
+	}
+	if bboxSet == len(bbox) {
+		minLat, minLon, maxLat, maxLon := *req.MinLatitude, *req.MinLongitude, *req.MaxLatitude, *req.MaxLongitude
+		if err := helper.ValidateCoordinates(minLat, minLon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: %w", err)
+		}
+		if err := helper.ValidateCoordinates(maxLat, maxLon); err != nil {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: %w", err)
+		}
+		if minLat > maxLat {
+			return nil, storage.Errorf(storage.ErrInvalidArgument, "invalid bounding box: min_latitude is greater than max_latitude")
+		}
+
+		filter += " AND m.latitude BETWEEN ? AND ?"
+		args = append(args, minLat, maxLat)
+		filter += longitudeRange(minLon, maxLon)
+		args = append(args, minLon, maxLon)
+	}
+
+	if req.PlaceName != "" {
+		filter += " AND m.place_name LIKE ?"
+		args = append(args, "%"+req.PlaceName+"%")
+	}
+
+	if req.Privacy != "" {
+		filter += " AND m.privacy = ?"
+		args = append(args, req.Privacy)
+	}
+
+	cond.filter = filter
+	cond.args = args
+	return cond, nil
+}
+
+// distanceExpr is the haversine distance in meters between a memory and the
+// point given by distanceArgs, the same formula the Postgres repository
+// uses.
+var distanceExpr = fmt.Sprintf(`(%f * 2 * asin(min(1, sqrt(
+		power(sin(radians(m.latitude - ?) / 2), 2) +
+		cos(radians(?)) * cos(radians(m.latitude)) * power(sin(radians(m.longitude - ?) / 2), 2)))))`,
+	helper.EarthRadiusMeters)
+
+// distanceArgs returns the placeholder values of distanceExpr.
+func distanceArgs(lat, lon float64) []interface{} {
+	return []interface{}{lat, lat, lon}
+}
+
+// longitudeRange returns the longitude condition for a box edge pair. A west
+// edge greater than the east edge means the range crosses the antimeridian.
+func longitudeRange(minLon, maxLon float64) string {
+	if minLon <= maxLon {
+		return " AND m.longitude BETWEEN ? AND ?"
+	}
+	return " AND (m.longitude >= ? OR m.longitude <= ?)"
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// matchQuery translates a search term in websearch_to_tsquery syntax into an
+// FTS5 query: words are ANDed, "or" separates alternatives and a leading "-"
+// excludes a word. Every word is quoted, so user input can never be a syntax
+// error.
+func matchQuery(term string) string {
+	var (
+		groups        []string
+		must, mustNot []string
+	)
+	flush := func() {
+		if len(must) > 0 {
+			group := strings.Join(must, " AND ")
+			for _, word := range mustNot {
+				group += " NOT " + word
+			}
+			groups = append(groups, "("+group+")")
+		}
+		must, mustNot = nil, nil
+	}
+
+	for _, field := range strings.Fields(term) {
+		if strings.EqualFold(field, "or") {
+			flush()
+			continue
+		}
+		negate := strings.HasPrefix(field, "-")
+		for _, word := range wordPattern.FindAllString(field, -1) {
+			quoted := `"` + word + `"`
+			if negate {
+				mustNot = append(mustNot, quoted)
+			} else {
+				must = append(must, quoted)
+			}
+		}
+	}
+	flush()
+
+	if len(groups) == 0 {
+		// Nothing to search for matches nothing, like an empty tsquery.
+		return `""`
+	}
+	return strings.Join(groups, " OR ")
+}
+
+func (r *MemoryRepo) UpdateMemory(ctx context.Context, memory *models.UpdateMemoryModel) error {
+	return inTx(ctx, r.db, func(tx querier) error {
+		old, err := liveMemory(ctx, tx, memory.ID, memory.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+
+		updated := old.clone()
+		updated.UserID = memory.UserID
+		updated.Title = memory.Title
+		updated.Description = memory.Description
+		updated.Date = memory.Date.UTC().Truncate(time.Microsecond)
+		updated.Tags = memory.Tags
+		updated.Latitude = memory.Latitude
+		updated.Longitude = memory.Longitude
+		updated.PlaceName = memory.PlaceName
+		updated.Privacy = memory.Privacy
+		if memory.Language != "" {
+			updated.Language = memory.Language
+		}
+
+		return update(ctx, tx, old, updated, memory.ChangedBy)
+	})
+}
+
+func (r *MemoryRepo) PatchMemory(ctx context.Context, memory *models.PatchMemoryModel) error {
+	if memory.Title == nil && memory.Description == nil && memory.Date == nil &&
+		memory.Tags == nil && memory.Latitude == nil && memory.Longitude == nil &&
+		memory.PlaceName == nil && memory.Privacy == nil && memory.Language == nil {
+		return storage.Errorf(storage.ErrInvalidArgument, "at least one field to update is required")
+	}
+
+	return inTx(ctx, r.db, func(tx querier) error {
+		old, err := liveMemory(ctx, tx, memory.ID, memory.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+
+		updated := old.clone()
+
+		if memory.Title != nil {
+			updated.Title = *memory.Title
+		}
+
+		if memory.Description != nil {
+			updated.Description = *memory.Description
+		}
+
+		if memory.Date != nil {
+			updated.Date = memory.Date.UTC().Truncate(time.Microsecond)
+		}
+
+		if memory.Tags != nil {
+			updated.Tags = *memory.Tags
+		}
+
+		if memory.Latitude != nil {
+			updated.Latitude = memory.Latitude
+		}
+
+		if memory.Longitude != nil {
+			updated.Longitude = memory.Longitude
+		}
+
+		if memory.PlaceName != nil {
+			updated.PlaceName = *memory.PlaceName
+		}
+
+		if memory.Privacy != nil {
+			updated.Privacy = *memory.Privacy
+		}
+
+		if memory.Language != nil {
+			updated.Language = *memory.Language
+		}
+
+		return update(ctx, tx, old, updated, memory.ChangedBy)
+	})
+}
+
+// liveMemory returns the memory with the given id unless it is missing or
+// in the trash (sql.ErrNoRows) or no longer at a non-zero expected version
+// (storage.ErrVersionConflict).
+func liveMemory(ctx context.Context, tx querier, id string, expectedVersion int64) (*memoryRow, error) {
+	m, err := getMemory(ctx, tx, `m.id = ? AND m.deleted_at IS NULL`, id)
+	if err != nil {
+		return nil, err
+	}
+	if expectedVersion != 0 && m.Version != expectedVersion {
+		return nil, versionConflict("memories", id, m.Version, expectedVersion)
+	}
+	return m, nil
+}
+
+// update writes updated over old, bumps the version and records the change
+// as a revision.
+func update(ctx context.Context, tx querier, old, updated *memoryRow, changedBy string) error {
+	updated.UpdatedAt = now()
+	updated.Version = old.Version + 1
+
+	query := `
+		UPDATE memories
+		SET
+			user_id = ?,
+			title = ?,
+			description = ?,
+			date = ?,
+			tags = ?,
+			latitude = ?,
+			longitude = ?,
+			place_name = ?,
+			privacy = ?,
+			language = ?,
+			updated_at = ?,
+			deleted_at = ?,
+			version = ?
+		WHERE id = ? AND version = ?
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		updated.UserID,
+		updated.Title,
+		updated.Description,
+		encodeTime(updated.Date),
+		encodeTags(updated.Tags),
+		updated.Latitude,
+		updated.Longitude,
+		updated.PlaceName,
+		updated.Privacy,
+		updated.Language,
+		encodeTime(updated.UpdatedAt),
+		encodeNullTime(updated.DeletedAt),
+		updated.Version,
+		updated.ID,
+		old.Version,
+	)
+	if err != nil {
+		return err
+	}
+	if err := expectAffected(result); err != nil {
+		return err
+	}
+
+	return recordRevision(ctx, tx, old, updated, changedBy)
+}
+
+// DeleteMemory moves a memory to the trash together with its media and
+// comments in a single transaction and reports how many children went with
+// it. All of them share the same deleted_at, so RestoreMemory can bring back
+// exactly the children that were trashed with the memory.
+func (r *MemoryRepo) DeleteMemory(ctx context.Context, id string) (*memory.DeleteMemoryResponse, error) {
+	resp := &memory.DeleteMemoryResponse{Success: true}
+
+	err := inTx(ctx, r.db, func(tx querier) error {
+		old, err := liveMemory(ctx, tx, id, 0)
+		if err != nil {
+			return err
+		}
+
+		ts := now()
+		updated := old.clone()
+		updated.DeletedAt = &ts
+		if err := update(ctx, tx, old, updated, ""); err != nil {
+			return err
+		}
+		deletedAt := encodeTime(ts)
+
+		media, err := tx.ExecContext(ctx, `
+			UPDATE media
+			SET deleted_at = ?
+			WHERE memory_id = ? AND deleted_at IS NULL
+		`, deletedAt, id)
+		if err != nil {
+			return err
+		}
+
+		comments, err := tx.ExecContext(ctx, `
+			UPDATE comments
+			SET deleted_at = ?, updated_at = ?, version = version + 1
+			WHERE memory_id = ? AND deleted_at IS NULL
+		`, deletedAt, deletedAt, id)
+		if err != nil {
+			return err
+		}
+
+		deletedMedia, err := media.RowsAffected()
+		if err != nil {
+			return err
+		}
+		deletedComments, err := comments.RowsAffected()
+		if err != nil {
+			return err
+		}
+		resp.DeletedMedia = int32(deletedMedia)
+		resp.DeletedComments = int32(deletedComments)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *MemoryRepo) GetDeletedMemories(ctx context.Context, req *memory.GetDeletedMemoriesRequest) (*memory.GetAllMemoriesResponse, error) {
+	var args []interface{}
+	filter := ""
+
+	if req.UserId != "" {
+		filter += " AND m.user_id = ?"
+		args = append(args, req.UserId)
+	}
+
+	var total int32
+	countQuery := `SELECT COUNT(*) FROM memories m WHERE m.deleted_at IS NOT NULL` + filter
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	query := `SELECT ` + memoryColumns + ` FROM memories m WHERE m.deleted_at IS NOT NULL` + filter +
+		` ORDER BY m.deleted_at DESC, m.id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []*memory.Memory
+	for rows.Next() {
+		m, err := scanMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, m.toProto())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.GetAllMemoriesResponse{
+		Memories: memories,
+		Count:    total,
+	}, nil
+}
+
+// RestoreMemory takes a memory out of the trash along with the media and
+// comments that were trashed together with it.
+func (r *MemoryRepo) RestoreMemory(ctx context.Context, id string) error {
+	return inTx(ctx, r.db, func(tx querier) error {
+		old, err := getMemory(ctx, tx, `m.id = ? AND m.deleted_at IS NOT NULL`, id)
+		if err != nil {
+			return err
+		}
+
+		updated := old.clone()
+		updated.DeletedAt = nil
+		if err := update(ctx, tx, old, updated, ""); err != nil {
+			return err
+		}
+		deletedAt := encodeTime(*old.DeletedAt)
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE media
+			SET deleted_at = NULL
+			WHERE memory_id = ? AND deleted_at = ?
+		`, id, deletedAt); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE comments
+			SET deleted_at = NULL, updated_at = ?, version = version + 1
+			WHERE memory_id = ? AND deleted_at = ?
+		`, encodeTime(updated.UpdatedAt), id, deletedAt)
+		return err
+	})
+}
+
+// PurgeMemories permanently deletes memories that were trashed before the
+// given time. Their media, comments and revisions go with them through the
+// ON DELETE CASCADE foreign keys.
+func (r *MemoryRepo) PurgeMemories(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM memories
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+	`, encodeTime(before))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// recordRevision stores the change from old to updated as a revision. A nil
+// old records the creation of the memory. Changes that touch no tracked
+// field are not recorded. The editor defaults to the memory owner.
+func recordRevision(ctx context.Context, tx querier, old, updated *memoryRow, changedBy string) error {
+	newFields := revisionFields(updated)
+	var oldFields map[string]json.RawMessage
+	if old != nil {
+		oldFields = revisionFields(old)
+	}
+
+	var changed []string
+	for field, value := range newFields {
+		if old == nil || string(oldFields[field]) != string(value) {
+			changed = append(changed, field)
+		}
+	}
+	if old != nil && len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+
+	oldValues := make(map[string]json.RawMessage)
+	newValues := make(map[string]json.RawMessage)
+	for _, field := range changed {
+		if old != nil {
+			oldValues[field] = oldFields[field]
+		}
+		newValues[field] = newFields[field]
+	}
+
+	if changedBy == "" {
+		changedBy = updated.UserID
+	}
+
+	encoded := make([][]byte, 0, 4)
+	for _, v := range []interface{}{changed, oldValues, newValues, updated} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		encoded = append(encoded, b)
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO memory_revisions (
+			id,
+			memory_id,
+			version,
+			changed_by,
+			changed_fields,
+			old_values,
+			new_values,
+			snapshot,
+			created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		uuid.NewString(),
+		updated.ID,
+		updated.Version,
+		changedBy,
+		string(encoded[0]),
+		string(encoded[1]),
+		string(encoded[2]),
+		string(encoded[3]),
+		encodeTime(updated.UpdatedAt),
+	)
+	return err
+}
+
+// revisionFields returns the JSON encoded fields of a memory that revisions
+// track. Bookkeeping fields are left out.
+func revisionFields(m *memoryRow) map[string]json.RawMessage {
+	fields := map[string]interface{}{
+		"user_id":     m.UserID,
+		"title":       m.Title,
+		"description": m.Description,
+		"date":        m.Date,
+		"tags":        m.Tags,
+		"latitude":    m.Latitude,
+		"longitude":   m.Longitude,
+		"place_name":  m.PlaceName,
+		"privacy":     m.Privacy,
+		"language":    m.Language,
+		"created_at":  m.CreatedAt,
+		"deleted_at":  m.DeletedAt,
+	}
+
+	encoded := make(map[string]json.RawMessage, len(fields))
+	for field, value := range fields {
+		b, _ := json.Marshal(value)
+		encoded[field] = b
+	}
+	return encoded
+}
+
+// ListMemoryRevisions lists the recorded changes of a memory, newest first.
+func (r *MemoryRepo) ListMemoryRevisions(ctx context.Context, req *memory.ListMemoryRevisionsRequest) (*memory.ListMemoryRevisionsResponse, error) {
+	var total int32
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM memory_revisions WHERE memory_id = ?`, req.MemoryId).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := helper.Pagination(req.Page, req.Limit)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id,
+			memory_id,
+			version,
+			changed_by,
+			changed_fields,
+			old_values,
+			new_values,
+			created_at
+		FROM memory_revisions
+		WHERE memory_id = ?
+		ORDER BY created_at DESC, version DESC
+		LIMIT ? OFFSET ?
+	`, req.MemoryId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*memory.MemoryRevision
+	for rows.Next() {
+		var (
+			revision      memory.MemoryRevision
+			changedFields string
+			oldValues     string
+			newValues     string
+			createdAt     sql.NullString
+		)
+		err := rows.Scan(
+			&revision.Id,
+			&revision.MemoryId,
+			&revision.Version,
+			&revision.ChangedBy,
+			&changedFields,
+			&oldValues,
+			&newValues,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			fields      []string
+			oldValueMap map[string]json.RawMessage
+			newValueMap map[string]json.RawMessage
+		)
+		if err := json.Unmarshal([]byte(changedFields), &fields); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(oldValues), &oldValueMap); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(newValues), &newValueMap); err != nil {
+			return nil, err
+		}
+
+		for _, field := range fields {
+			change := &memory.FieldChange{Field: field}
+			if v, ok := oldValueMap[field]; ok {
+				change.OldValue = string(v)
+			}
+			if v, ok := newValueMap[field]; ok {
+				change.NewValue = string(v)
+			}
+			revision.Changes = append(revision.Changes, change)
+		}
+		revision.CreatedAt = formatTime(createdAt)
+		revisions = append(revisions, &revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &memory.ListMemoryRevisionsResponse{
+		Revisions: revisions,
+		Count:     total,
+	}, nil
+}
+
+// GetMemoryAsOf returns the memory as it was at the given time, rebuilt from
+// the snapshot of the latest revision recorded at or before it.
+func (r *MemoryRepo) GetMemoryAsOf(ctx context.Context, id string, asOf time.Time) (*memory.Memory, error) {
+	var snapshot string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT snapshot
+		FROM memory_revisions
+		WHERE memory_id = ? AND created_at <= ?
+		ORDER BY created_at DESC, version DESC
+		LIMIT 1
+	`, id, encodeTime(asOf)).Scan(&snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var m memoryRow
+	if err := json.Unmarshal([]byte(snapshot), &m); err != nil {
+		return nil, err
+	}
+
+	return m.toProto(), nil
+}
+
+// RestoreMemoryRevision writes the content of an earlier revision back to
+// the memory. The restore is itself recorded as a new revision, so nothing
+// in the history is lost.
+func (r *MemoryRepo) RestoreMemoryRevision(ctx context.Context, req *memory.RestoreMemoryRevisionRequest) error {
+	return inTx(ctx, r.db, func(tx querier) error {
+		var encoded string
+		err := tx.QueryRowContext(ctx, `
+			SELECT snapshot
+			FROM memory_revisions
+			WHERE id = ? AND memory_id = ?
+		`, req.RevisionId, req.MemoryId).Scan(&encoded)
+		if err != nil {
+			return err
+		}
+
+		var snapshot memoryRow
+		if err := json.Unmarshal([]byte(encoded), &snapshot); err != nil {
+			return err
+		}
+
+		old, err := liveMemory(ctx, tx, req.MemoryId, req.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+
+		updated := old.clone()
+		updated.Title = snapshot.Title
+		updated.Description = snapshot.Description
+		updated.Date = snapshot.Date
+		updated.Tags = snapshot.Tags
+		updated.Latitude = snapshot.Latitude
+		updated.Longitude = snapshot.Longitude
+		updated.PlaceName = snapshot.PlaceName
+		updated.Privacy = snapshot.Privacy
+		updated.Language = snapshot.Language
+
+		return update(ctx, tx, old, updated, req.ChangedBy)
+	})
+}
@@ -0,0 +1,110 @@
+-- Schema of the SQLite storage backend. It is applied on every startup, so
+-- every statement must be idempotent.
+--
+-- Timestamps are stored as UTC text with a fixed microsecond precision
+-- (2006-01-02T15:04:05.000000Z), which sorts and compares correctly as text.
+-- Tags are stored as a JSON array.
+
+CREATE TABLE IF NOT EXISTS memories (
+    id          TEXT PRIMARY KEY,
+    user_id     TEXT NOT NULL,
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    date        TEXT NOT NULL,
+    tags        TEXT NOT NULL DEFAULT '[]',
+    latitude    REAL,
+    longitude   REAL,
+    place_name  TEXT NOT NULL DEFAULT '',
+    privacy     TEXT NOT NULL DEFAULT '',
+    language    TEXT NOT NULL DEFAULT 'english',
+    created_at  TEXT NOT NULL,
+    updated_at  TEXT NOT NULL,
+    deleted_at  TEXT,
+    version     INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_memories_user_id ON memories (user_id);
+CREATE INDEX IF NOT EXISTS idx_memories_date_id ON memories (date DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_memories_location ON memories (latitude, longitude);
+CREATE INDEX IF NOT EXISTS idx_memories_deleted_at ON memories (deleted_at);
+
+CREATE TABLE IF NOT EXISTS media (
+    id         TEXT PRIMARY KEY,
+    memory_id  TEXT NOT NULL REFERENCES memories (id) ON DELETE CASCADE,
+    type       TEXT NOT NULL,
+    url        TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    deleted_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_memory_id ON media (memory_id);
+CREATE INDEX IF NOT EXISTS idx_media_created_at_id ON media (created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         TEXT PRIMARY KEY,
+    memory_id  TEXT NOT NULL REFERENCES memories (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL,
+    content    TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    deleted_at TEXT,
+    version    INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_memory_id ON comments (memory_id);
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments (user_id);
+CREATE INDEX IF NOT EXISTS idx_comments_created_at_id ON comments (created_at DESC, id DESC);
+
+-- Every change of a memory. changed_fields is a JSON array, old_values and
+-- new_values are JSON objects keyed by field and snapshot is the full memory
+-- after the change.
+CREATE TABLE IF NOT EXISTS memory_revisions (
+    id             TEXT PRIMARY KEY,
+    memory_id      TEXT NOT NULL REFERENCES memories (id) ON DELETE CASCADE,
+    version        INTEGER NOT NULL,
+    changed_by     TEXT NOT NULL,
+    changed_fields TEXT NOT NULL,
+    old_values     TEXT NOT NULL,
+    new_values     TEXT NOT NULL,
+    snapshot       TEXT NOT NULL,
+    created_at     TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_revisions_memory_id_created_at ON memory_revisions (memory_id, created_at DESC);
+
+-- Ids of the Kafka commands that have been applied, recorded in the
+-- transaction of their writes.
+CREATE TABLE IF NOT EXISTS processed_commands (
+    id           TEXT PRIMARY KEY,
+    operation    TEXT NOT NULL,
+    processed_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_commands_processed_at ON processed_commands (processed_at);
+
+-- Full-text index of memories, kept in sync by the triggers below. The
+-- column order gives the bm25 weights used for ranking: title, tags,
+-- description, place name.
+CREATE VIRTUAL TABLE IF NOT EXISTS memories_fts USING fts5(
+    memory_id UNINDEXED,
+    title,
+    tags,
+    description,
+    place_name,
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS memories_fts_insert AFTER INSERT ON memories BEGIN
+    INSERT INTO memories_fts (memory_id, title, tags, description, place_name)
+    VALUES (new.id, new.title, new.tags, new.description, new.place_name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS memories_fts_update AFTER UPDATE OF title, tags, description, place_name ON memories BEGIN
+    DELETE FROM memories_fts WHERE memory_id = old.id;
+    INSERT INTO memories_fts (memory_id, title, tags, description, place_name)
+    VALUES (new.id, new.title, new.tags, new.description, new.place_name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS memories_fts_delete AFTER DELETE ON memories BEGIN
+    DELETE FROM memories_fts WHERE memory_id = old.id;
+END;
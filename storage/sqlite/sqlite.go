@@ -0,0 +1,278 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/time_capsule/memory-service/config"
+	"github.com/time_capsule/memory-service/storage"
+	_ "modernc.org/sqlite" // pure Go driver, registered as "sqlite"
+)
+
+//go:embed schema.sql
+var schema string
+
+// timeLayout is how timestamps are stored: UTC with a fixed microsecond
+// precision, so they compare correctly as text and round-trip like the
+// Postgres timestamps do.
+const timeLayout = "2006-01-02T15:04:05.000000Z"
+
+// Storage implements the storage.StorageI interface for SQLite.
+type Storage struct {
+	db       querier
+	MemoryS  storage.MemoryI
+	MediaS   storage.MediaI
+	CommentS storage.CommentI
+	CommandS storage.CommandI
+}
+
+// NewSQLiteStorage opens the SQLite database at cfg.SQLitePath, creating it
+// and its schema if needed.
+func NewSQLiteStorage(cfg config.Config) (storage.StorageI, error) {
+	db, err := Open(context.Background(), cfg.SQLitePath)
+	if err != nil {
+		slog.Warn("Unable to open SQLite database", "path", cfg.SQLitePath, "err", err)
+		return nil, err
+	}
+
+	return NewStorage(db), nil
+}
+
+// NewStorage creates a SQLite storage instance on top of an open database
+// or transaction.
+func NewStorage(db querier) *Storage {
+	return &Storage{
+		db:       db,
+		MemoryS:  classifier.Memory(NewMemoryRepo(db)),
+		MediaS:   classifier.Media(NewMediaRepo(db)),
+		CommentS: classifier.Comment(NewCommentRepo(db)),
+		CommandS: classifier.Command(NewCommandRepo(db)),
+	}
+}
+
+// Open opens the SQLite database at path and applies the embedded schema.
+// Foreign keys are enforced, and a single connection serializes all access,
+// which is what SQLite handles best and keeps read-modify-write
+// transactions free of lock upgrades.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	pragmas.Add("_pragma", "busy_timeout(5000)")
+	pragmas.Add("_pragma", "journal_mode(WAL)")
+
+	dsn := path
+	if strings.Contains(dsn, "?") {
+		dsn += "&" + pragmas.Encode()
+	} else {
+		dsn += "?" + pragmas.Encode()
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := EnsureSchema(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	return db, nil
+}
+
+// EnsureSchema creates the tables, indexes and triggers the repositories
+// rely on. It is idempotent and runs on every startup.
+func EnsureSchema(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, schema)
+	return err
+}
+
+// Memory returns the MemoryI implementation for SQLite.
+func (s *Storage) Memory() storage.MemoryI {
+	return s.MemoryS
+}
+
+// Media returns the MediaI implementation for SQLite.
+func (s *Storage) Media() storage.MediaI {
+	return s.MediaS
+}
+
+// Comment returns the CommentI implementation for SQLite.
+func (s *Storage) Comment() storage.CommentI {
+	return s.CommentS
+}
+
+// Command returns the CommandI implementation for SQLite.
+func (s *Storage) Command() storage.CommandI {
+	return s.CommandS
+}
+
+// WithTx runs fn with repositories bound to a single transaction, which is
+// committed if fn returns nil and rolled back if it fails or panics. Called
+// on a transactional storage it runs fn in a savepoint of the enclosing
+// transaction. The database has a single connection, so fn must only use
+// the storage it is given.
+func (s *Storage) WithTx(ctx context.Context, fn func(tx storage.StorageI) error) error {
+	err := inTx(ctx, s.db, func(tx querier) error {
+		return fn(NewStorage(tx))
+	})
+	return classifier.Classify(err)
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// inTx runs fn in a transaction on db that is committed if fn returns nil
+// and rolled back if it fails or panics. When db already is a transaction,
+// fn runs in a savepoint of it instead.
+func inTx(ctx context.Context, db querier, fn func(tx querier) error) error {
+	conn, ok := db.(*sql.DB)
+	if !ok {
+		return inSavepoint(ctx, db, fn)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// inSavepoint runs fn in a savepoint of the transaction tx, undoing only
+// the work of fn if it fails or panics.
+func inSavepoint(ctx context.Context, tx querier, fn func(tx querier) error) error {
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT nested`); err != nil {
+		return err
+	}
+	rollback := func() {
+		tx.ExecContext(ctx, `ROLLBACK TO nested`)
+		tx.ExecContext(ctx, `RELEASE nested`)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		rollback()
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `RELEASE nested`)
+	return err
+}
+
+// ensureMemoryExists returns storage.ErrMemoryNotFound unless the memory
+// exists and is not in the trash. Media and comments use it to refuse
+// pointing at missing memories.
+func ensureMemoryExists(ctx context.Context, db querier, id string) error {
+	var exists bool
+	err := db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM memories WHERE id = ? AND deleted_at IS NULL)
+	`, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", storage.ErrMemoryNotFound, id)
+	}
+	return nil
+}
+
+// missingOrConflict explains why a versioned write to table matched no rows:
+// either the row is gone (sql.ErrNoRows) or it has moved past the expected
+// version (storage.ErrVersionConflict).
+func missingOrConflict(ctx context.Context, db querier, table, id string, expectedVersion int64) error {
+	if expectedVersion == 0 {
+		return sql.ErrNoRows
+	}
+
+	var version int64
+	err := db.QueryRowContext(ctx, `SELECT version FROM `+table+` WHERE id = ? AND deleted_at IS NULL`, id).Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	return versionConflict(table, id, version, expectedVersion)
+}
+
+func versionConflict(table, id string, version, expectedVersion int64) error {
+	return fmt.Errorf("%w: %s %s is at version %d, expected %d", storage.ErrVersionConflict, table, id, version, expectedVersion)
+}
+
+// now returns the current time as stored.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// encodeTime formats t for storage.
+func encodeTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// encodeOptionalTime formats t for storage; the zero time becomes NULL, which
+// update statements use to keep the stored value.
+func encodeOptionalTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: encodeTime(t), Valid: true}
+}
+
+// encodeNullTime formats t for storage; nil becomes NULL.
+func encodeNullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: encodeTime(*t), Valid: true}
+}
+
+// decodeTime parses a stored timestamp.
+func decodeTime(s string) (time.Time, error) {
+	return time.Parse(timeLayout, s)
+}
+
+// decodeNullTime parses a stored NULL-able timestamp.
+func decodeNullTime(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	t, err := decodeTime(s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// formatTime formats a stored timestamp like helper.DateToString does for
+// SQL NULL-able columns: NULL becomes an empty string.
+func formatTime(s sql.NullString) string {
+	t, err := decodeNullTime(s)
+	if err != nil || t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
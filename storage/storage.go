@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/time_capsule/memory-service/genproto/memory"
 	"github.com/time_capsule/memory-service/models"
@@ -12,34 +13,75 @@ type StorageI interface {
 	Memory() MemoryI
 	Media() MediaI
 	Comment() CommentI
+	Command() CommandI
+
+	// WithTx runs fn with a storage whose repositories share a single
+	// transaction. The transaction is committed if fn returns nil and rolled
+	// back if fn returns an error or panics; the error or panic is passed
+	// on. Calling WithTx on the storage given to fn runs inside the
+	// enclosing transaction.
+	WithTx(ctx context.Context, fn func(tx StorageI) error) error
 }
 
 // MemoryI defines methods for interacting with memory data.
 type MemoryI interface {
 	CreateMemory(ctx context.Context, memory *models.CreateMemoryModel) (string, error)
+	// BulkCreateMemories creates all memories or, if one of them cannot be
+	// created, none, and returns their ids in order.
+	BulkCreateMemories(ctx context.Context, memories []*models.CreateMemoryModel) ([]string, error)
 	GetMemoryByID(ctx context.Context, id string) (*memory.Memory, error)
-	GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) ([]*memory.Memory, error)
+	GetAllMemories(ctx context.Context, req *memory.GetAllMemoriesRequest) (*memory.GetAllMemoriesResponse, error)
 	UpdateMemory(ctx context.Context, memory *models.UpdateMemoryModel) error
 	PatchMemory(ctx context.Context, memory *models.PatchMemoryModel) error
-	DeleteMemory(ctx context.Context, id string) error
+	DeleteMemory(ctx context.Context, id string) (*memory.DeleteMemoryResponse, error)
+	GetDeletedMemories(ctx context.Context, req *memory.GetDeletedMemoriesRequest) (*memory.GetAllMemoriesResponse, error)
+	RestoreMemory(ctx context.Context, id string) error
+	PurgeMemories(ctx context.Context, before time.Time) (int64, error)
+	ListMemoryRevisions(ctx context.Context, req *memory.ListMemoryRevisionsRequest) (*memory.ListMemoryRevisionsResponse, error)
+	GetMemoryAsOf(ctx context.Context, id string, asOf time.Time) (*memory.Memory, error)
+	RestoreMemoryRevision(ctx context.Context, req *memory.RestoreMemoryRevisionRequest) error
 }
 
 // MediaI defines methods for interacting with media data.
 type MediaI interface {
 	CreateMedia(ctx context.Context, media *models.CreateMediaModel) (string, error)
+	// BulkCreateMedia creates all media or, if one of them cannot be
+	// created, none, and returns their ids in order.
+	BulkCreateMedia(ctx context.Context, media []*models.CreateMediaModel) ([]string, error)
 	GetMediaByID(ctx context.Context, id string) (*memory.Media, error)
-	GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) ([]*memory.Media, error)
+	GetAllMedia(ctx context.Context, req *memory.GetAllMediaRequest) (*memory.GetAllMediaResponse, error)
 	UpdateMedia(ctx context.Context, media *models.UpdateMediaModel) error
 	PatchMedia(ctx context.Context, media *models.PatchMediaModel) error
 	DeleteMedia(ctx context.Context, id string) error
+	GetDeletedMedia(ctx context.Context, req *memory.GetDeletedMediaRequest) (*memory.GetAllMediaResponse, error)
+	RestoreMedia(ctx context.Context, id string) error
+	PurgeMedia(ctx context.Context, before time.Time) (int64, error)
 }
 
 // CommentI defines methods for interacting with comment data.
 type CommentI interface {
 	CreateComment(ctx context.Context, comment *models.CreateCommentModel) (string, error)
+	// BulkCreateComments creates all comments or, if one of them cannot be
+	// created, none, and returns their ids in order.
+	BulkCreateComments(ctx context.Context, comments []*models.CreateCommentModel) ([]string, error)
 	GetCommentByID(ctx context.Context, id string) (*memory.Comment, error)
-	GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) ([]*memory.Comment, error)
+	GetAllComments(ctx context.Context, req *memory.GetAllCommentsRequest) (*memory.GetAllCommentsResponse, error)
 	UpdateComment(ctx context.Context, comment *models.UpdateCommentModel) error
 	PatchComment(ctx context.Context, comment *models.PatchCommentModel) error
 	DeleteComment(ctx context.Context, id string) error
+	GetDeletedComments(ctx context.Context, req *memory.GetDeletedCommentsRequest) (*memory.GetAllCommentsResponse, error)
+	RestoreComment(ctx context.Context, id string) error
+	PurgeComments(ctx context.Context, before time.Time) (int64, error)
+}
+
+// CommandI records the ids of processed commands, so that a command that is
+// delivered again is not applied twice. Marking a command processed in the
+// transaction of its writes makes both take effect together.
+type CommandI interface {
+	// MarkCommandProcessed records the command with the given id as
+	// processed. It fails with ErrAlreadyExists if it already was.
+	MarkCommandProcessed(ctx context.Context, id, operation string) error
+	// PurgeCommands forgets the commands processed before the given time
+	// and returns how many it forgot.
+	PurgeCommands(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/storage/inmemory"
+	mongostorage "github.com/time_capsule/memory-service/storage/mongo"
+	"github.com/time_capsule/memory-service/storage/postgres"
+	"github.com/time_capsule/memory-service/storage/sqlite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testBackend is a storage backend the repository tests run against.
+type testBackend struct {
+	storage.StorageI
+	remove func(ctx context.Context, table, id string) error // hard-deletes test leftovers
+}
+
+// forEachBackend runs fn as a subtest against every backend listed in
+// TEST_STORAGE_DRIVERS (comma separated, default "inmemory,sqlite"). The repository
+// tests in this package are the conformance suite every storage.StorageI
+// implementation has to pass.
+func forEachBackend(t *testing.T, fn func(t *testing.T, db *testBackend)) {
+	drivers := os.Getenv("TEST_STORAGE_DRIVERS")
+	if drivers == "" {
+		drivers = "inmemory,sqlite"
+	}
+
+	for _, driver := range strings.Split(drivers, ",") {
+		driver = strings.TrimSpace(driver)
+		t.Run(driver, func(t *testing.T) {
+			fn(t, newTestBackend(t, driver))
+		})
+	}
+}
+
+func newTestBackend(t *testing.T, driver string) *testBackend {
+	switch driver {
+	case "inmemory":
+		return &testBackend{
+			StorageI: inmemory.NewInMemoryStorage(),
+			// Every test gets a fresh store, so there is nothing to clean up.
+			remove: func(ctx context.Context, table, id string) error {
+				return nil
+			},
+		}
+
+	case "sqlite":
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "memory.db"))
+		if err != nil {
+			t.Fatalf("Unable to open SQLite database: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		return &testBackend{
+			StorageI: sqlite.NewStorage(db),
+			remove: func(ctx context.Context, table, id string) error {
+				_, err := db.ExecContext(ctx, "DELETE FROM "+table+" WHERE id = ?", id)
+				return err
+			},
+		}
+
+	case "postgres":
+		db := createDBConnection(t)
+		t.Cleanup(db.Close)
+
+		return &testBackend{
+			StorageI: postgres.NewStorage(db),
+			remove: func(ctx context.Context, table, id string) error {
+				_, err := db.Exec(ctx, "DELETE FROM "+table+" WHERE id = $1", id)
+				return err
+			},
+		}
+
+	case "mongo":
+		db := createMongoConnection(t)
+
+		return &testBackend{
+			StorageI: mongostorage.NewStorage(db),
+			remove: func(ctx context.Context, collection, id string) error {
+				if _, err := db.Collection(collection).DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+					return err
+				}
+				// Postgres cascades; do the same for a memory's children.
+				if collection == "memories" {
+					for _, child := range []string{"media", "comments", "memory_revisions"} {
+						if _, err := db.Collection(child).DeleteMany(ctx, bson.M{"memory_id": id}); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Fatalf("unknown storage driver %q", driver)
+	return nil
+}
+
+func createDBConnection(t *testing.T) *pgxpool.Pool {
+
+	dbCon := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s",
+		"sayyidmuhammad",
+		"root",
+		"localhost",
+		5432,
+		"postgres",
+	)
+
+	// Connecting to postgres
+	db, err := pgxpool.New(context.Background(), dbCon)
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	return db
+}
+
+// createMongoConnection connects to the MongoDB given by TEST_MONGO_URI
+// (default localhost) and prepares the test database.
+func createMongoConnection(t *testing.T) *mongo.Database {
+	uri := os.Getenv("TEST_MONGO_URI")
+	if uri == "" {
+		uri = "mongodb://localhost:27017"
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("Unable to connect to MongoDB: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	db := client.Database("memory_test")
+	if err := mongostorage.EnsureIndexes(context.Background(), db); err != nil {
+		t.Fatalf("Unable to create MongoDB indexes: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+func TestBulkCreate(t *testing.T) {
+	forEachBackend(t, testBulkCreate)
+}
+
+func testBulkCreate(t *testing.T, db *testBackend) {
+	ctx := context.Background()
+
+	newMemory := func(title string) *models.CreateMemoryModel {
+		return &models.CreateMemoryModel{
+			UserID:  uuid.NewString(),
+			Title:   title,
+			Date:    time.Now(),
+			Tags:    []string{"bulk"},
+			Privacy: "public",
+		}
+	}
+
+	t.Run("BulkCreateMemories", func(t *testing.T) {
+		memories := []*models.CreateMemoryModel{newMemory("First"), newMemory("Second"), newMemory("Third")}
+		ids, err := db.Memory().BulkCreateMemories(ctx, memories)
+		assert.NoError(t, err)
+		assert.Len(t, ids, len(memories))
+		for i, id := range ids {
+			defer deleteMemory(t, db, id)
+
+			stored, err := db.Memory().GetMemoryByID(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, memories[i].Title, stored.Title)
+		}
+
+		// An id that is taken fails the batch.
+		_, err = db.Memory().BulkCreateMemories(ctx, []*models.CreateMemoryModel{newMemory("Fourth"), {
+			ID:      ids[0],
+			UserID:  uuid.NewString(),
+			Title:   "Taken",
+			Date:    time.Now(),
+			Privacy: "public",
+		}})
+		assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	})
+
+	t.Run("BulkCreateMediaAndComments", func(t *testing.T) {
+		memoryID, err := db.Memory().CreateMemory(ctx, newMemory("Parent"))
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, memoryID)
+
+		mediaIDs, err := db.Media().BulkCreateMedia(ctx, []*models.CreateMediaModel{
+			{MemoryID: memoryID, Type: "image", URL: "https://example.com/1.jpg"},
+			{MemoryID: memoryID, Type: "video", URL: "https://example.com/2.mp4"},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, mediaIDs, 2)
+		for _, id := range mediaIDs {
+			defer deleteMedia(t, db, id)
+
+			stored, err := db.Media().GetMediaByID(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, memoryID, stored.MemoryId)
+		}
+
+		commentIDs, err := db.Comment().BulkCreateComments(ctx, []*models.CreateCommentModel{
+			{MemoryID: memoryID, UserID: uuid.NewString(), Content: "First"},
+			{MemoryID: memoryID, UserID: uuid.NewString(), Content: "Second"},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, commentIDs, 2)
+		for _, id := range commentIDs {
+			defer deleteComment(t, db, id)
+
+			stored, err := db.Comment().GetCommentByID(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, memoryID, stored.MemoryId)
+		}
+	})
+
+	t.Run("UnknownMemoryCreatesNothing", func(t *testing.T) {
+		memoryID, err := db.Memory().CreateMemory(ctx, newMemory("Parent"))
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, memoryID)
+
+		mediaID := uuid.NewString()
+		_, err = db.Media().BulkCreateMedia(ctx, []*models.CreateMediaModel{
+			{ID: mediaID, MemoryID: memoryID, Type: "image", URL: "https://example.com/1.jpg"},
+			{MemoryID: uuid.NewString(), Type: "image", URL: "https://example.com/orphan.jpg"},
+		})
+		assert.ErrorIs(t, err, storage.ErrMemoryNotFound)
+		_, err = db.Media().GetMediaByID(ctx, mediaID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		commentID := uuid.NewString()
+		_, err = db.Comment().BulkCreateComments(ctx, []*models.CreateCommentModel{
+			{ID: commentID, MemoryID: memoryID, UserID: uuid.NewString(), Content: "Rolled back"},
+			{MemoryID: uuid.NewString(), UserID: uuid.NewString(), Content: "Orphan"},
+		})
+		assert.ErrorIs(t, err, storage.ErrMemoryNotFound)
+		_, err = db.Comment().GetCommentByID(ctx, commentID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
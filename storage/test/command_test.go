@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+func TestMarkCommandProcessed(t *testing.T) {
+	forEachBackend(t, testMarkCommandProcessed)
+}
+
+func testMarkCommandProcessed(t *testing.T, db *testBackend) {
+	ctx := context.Background()
+
+	t.Run("Duplicate", func(t *testing.T) {
+		id := uuid.NewString()
+		defer deleteCommand(t, db, id)
+
+		assert.NoError(t, db.Command().MarkCommandProcessed(ctx, id, "memory.create"))
+		err := db.Command().MarkCommandProcessed(ctx, id, "memory.create")
+		assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		id := uuid.NewString()
+		defer deleteCommand(t, db, id)
+
+		// A command whose writes are rolled back is not processed.
+		errAbort := errors.New("abort")
+		err := db.WithTx(ctx, func(tx storage.StorageI) error {
+			if err := tx.Command().MarkCommandProcessed(ctx, id, "memory.create"); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		assert.ErrorIs(t, err, errAbort)
+
+		assert.NoError(t, db.Command().MarkCommandProcessed(ctx, id, "memory.create"))
+	})
+}
+
+func TestPurgeCommands(t *testing.T) {
+	forEachBackend(t, testPurgeCommands)
+}
+
+func testPurgeCommands(t *testing.T, db *testBackend) {
+	ctx := context.Background()
+
+	id := uuid.NewString()
+	defer deleteCommand(t, db, id)
+	assert.NoError(t, db.Command().MarkCommandProcessed(ctx, id, "memory.create"))
+
+	// Nothing was processed an hour ago.
+	_, err := db.Command().PurgeCommands(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	err = db.Command().MarkCommandProcessed(ctx, id, "memory.create")
+	assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+
+	purged, err := db.Command().PurgeCommands(ctx, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, purged, int64(1))
+	assert.NoError(t, db.Command().MarkCommandProcessed(ctx, id, "memory.create"))
+}
+
+func deleteCommand(t *testing.T, db *testBackend, id string) {
+	assert.NoError(t, db.remove(context.Background(), "processed_commands", id))
+}
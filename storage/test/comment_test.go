@@ -6,19 +6,20 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
 	"github.com/time_capsule/memory-service/models"
-	"github.com/time_capsule/memory-service/storage/postgres"
+	"github.com/time_capsule/memory-service/storage"
 )
 
 func TestCommentRepo(t *testing.T) {
-	db := createDBConnection(t)
-	defer db.Close(context.Background())
+	forEachBackend(t, testCommentRepo)
+}
 
-	memoryRepo := postgres.NewMemoryRepo(db)
-	commentRepo := postgres.NewCommentRepo(db)
+func testCommentRepo(t *testing.T, db *testBackend) {
+	memoryRepo := db.Memory()
+	commentRepo := db.Comment()
 
 	t.Run("CreateComment", func(t *testing.T) {
 		// Create a test memory first
@@ -28,8 +29,8 @@ func TestCommentRepo(t *testing.T) {
 			Description: "This is a test memory for comment.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "comment"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -61,8 +62,8 @@ func TestCommentRepo(t *testing.T) {
 			Description: "This is a test memory for comment.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "comment"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -100,8 +101,8 @@ func TestCommentRepo(t *testing.T) {
 			Description: "This is a test memory for comment.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "comment"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -133,12 +134,20 @@ func TestCommentRepo(t *testing.T) {
 		// Test GetAllComments with no filters
 		commentList, err := commentRepo.GetAllComments(context.Background(), &memory.GetAllCommentsRequest{})
 		assert.NoError(t, err)
-		assert.GreaterOrEqual(t, len(commentList), 2) // At least 2 comments should be returned
+		assert.GreaterOrEqual(t, len(commentList.Comments), 2) // At least 2 comments should be returned
+		assert.GreaterOrEqual(t, commentList.Count, int32(2))
 
 		// Test GetAllComments with memoryID filter
 		commentList, err = commentRepo.GetAllComments(context.Background(), &memory.GetAllCommentsRequest{MemoryId: memoryID})
 		assert.NoError(t, err)
-		assert.GreaterOrEqual(t, len(commentList), 2) // At least 2 comments should be returned for this memory
+		assert.Len(t, commentList.Comments, 2) // Exactly the 2 comments of this memory
+		assert.Equal(t, int32(2), commentList.Count)
+
+		// Test GetAllComments pagination
+		commentList, err = commentRepo.GetAllComments(context.Background(), &memory.GetAllCommentsRequest{MemoryId: memoryID, Page: 2, Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, commentList.Comments, 1)
+		assert.Equal(t, int32(2), commentList.Count)
 
 		// Cleanup
 		defer deleteComment(t, db, createdID1)
@@ -154,8 +163,8 @@ func TestCommentRepo(t *testing.T) {
 			Description: "This is a test memory for comment.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "comment"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -195,6 +204,43 @@ func TestCommentRepo(t *testing.T) {
 		defer deleteMemory(t, db, memoryID)
 	})
 
+	t.Run("UpdateCommentKeepsCreatedAt", func(t *testing.T) {
+		memoryID, err := memoryRepo.CreateMemory(context.Background(), &models.CreateMemoryModel{
+			UserID:  uuid.New().String(),
+			Title:   "Test Memory for Comment",
+			Date:    time.Now(),
+			Privacy: "public",
+		})
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, memoryID)
+
+		userID := uuid.New().String()
+		createdID, err := commentRepo.CreateComment(context.Background(), &models.CreateCommentModel{
+			MemoryID: memoryID,
+			UserID:   userID,
+			Content:  "This is a test comment.",
+		})
+		assert.NoError(t, err)
+		defer deleteComment(t, db, createdID)
+
+		created, err := commentRepo.GetCommentByID(context.Background(), createdID)
+		assert.NoError(t, err)
+
+		// Without a creation time the stored one is kept.
+		err = commentRepo.UpdateComment(context.Background(), &models.UpdateCommentModel{
+			ID:       createdID,
+			MemoryID: memoryID,
+			UserID:   userID,
+			Content:  "This is an updated comment.",
+		})
+		assert.NoError(t, err)
+
+		updated, err := commentRepo.GetCommentByID(context.Background(), createdID)
+		assert.NoError(t, err)
+		assert.Equal(t, "This is an updated comment.", updated.Content)
+		assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+	})
+
 	t.Run("PatchComment", func(t *testing.T) {
 		// Create a test memory first
 		createMemoryModel := &models.CreateMemoryModel{
@@ -203,8 +249,8 @@ func TestCommentRepo(t *testing.T) {
 			Description: "This is a test memory for comment.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "comment"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -249,8 +295,8 @@ func TestCommentRepo(t *testing.T) {
 			Description: "This is a test memory for comment.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "comment"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -273,7 +319,7 @@ func TestCommentRepo(t *testing.T) {
 		assert.NoError(t, err)
 
 		_, err = commentRepo.GetCommentByID(context.Background(), createdID)
-		assert.ErrorIs(t, err, pgx.ErrNoRows) // Comment should not be found
+		assert.ErrorIs(t, err, storage.ErrNotFound) // Comment should not be found
 
 		// Cleanup (memory) - Comment should be already deleted
 		defer deleteMemory(t, db, memoryID)
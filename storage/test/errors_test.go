@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+func TestErrorKinds(t *testing.T) {
+	forEachBackend(t, testErrorKinds)
+}
+
+func testErrorKinds(t *testing.T, db *testBackend) {
+	ctx := context.Background()
+
+	memoryID, err := db.Memory().CreateMemory(ctx, &models.CreateMemoryModel{
+		UserID:  uuid.New().String(),
+		Title:   "Error Kinds",
+		Date:    time.Now(),
+		Privacy: "private",
+	})
+	assert.NoError(t, err)
+	defer deleteMemory(t, db, memoryID)
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := db.Memory().GetMemoryByID(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		_, err = db.Media().CreateMedia(ctx, &models.CreateMediaModel{
+			MemoryID: uuid.New().String(),
+			Type:     "image",
+			URL:      "https://example.com/image.jpg",
+		})
+		assert.ErrorIs(t, err, storage.ErrMemoryNotFound)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		_, err := db.Memory().CreateMemory(ctx, &models.CreateMemoryModel{
+			ID:      memoryID,
+			UserID:  uuid.New().String(),
+			Title:   "Duplicate",
+			Date:    time.Now(),
+			Privacy: "private",
+		})
+		assert.ErrorIs(t, err, storage.ErrAlreadyExists)
+	})
+
+	t.Run("InvalidArgument", func(t *testing.T) {
+		_, err := db.Memory().GetAllMemories(ctx, &memory.GetAllMemoriesRequest{StartDate: "yesterday"})
+		assert.ErrorIs(t, err, storage.ErrInvalidArgument)
+
+		_, err = db.Memory().GetAllMemories(ctx, &memory.GetAllMemoriesRequest{PageToken: "not a token"})
+		assert.ErrorIs(t, err, storage.ErrInvalidArgument)
+
+		err = db.Memory().PatchMemory(ctx, &models.PatchMemoryModel{ID: memoryID})
+		assert.ErrorIs(t, err, storage.ErrInvalidArgument)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		err := db.Memory().PatchMemory(ctx, &models.PatchMemoryModel{
+			ID:              memoryID,
+			Title:           helper.Ptr("Stale"),
+			ExpectedVersion: 42,
+		})
+		assert.ErrorIs(t, err, storage.ErrVersionConflict)
+		assert.ErrorIs(t, err, storage.ErrConflict)
+	})
+
+	t.Run("Kind", func(t *testing.T) {
+		assert.Equal(t, storage.ErrConflict, storage.Kind(storage.ErrVersionConflict))
+		assert.Nil(t, storage.Kind(context.Canceled))
+	})
+}
@@ -6,19 +6,20 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
 	"github.com/time_capsule/memory-service/models"
-	"github.com/time_capsule/memory-service/storage/postgres"
+	"github.com/time_capsule/memory-service/storage"
 )
 
 func TestMediaRepo(t *testing.T) {
-	db := createDBConnection(t)
-	defer db.Close(context.Background())
+	forEachBackend(t, testMediaRepo)
+}
 
-	memoryRepo := postgres.NewMemoryRepo(db)
-	mediaRepo := postgres.NewMediaRepo(db)
+func testMediaRepo(t *testing.T, db *testBackend) {
+	memoryRepo := db.Memory()
+	mediaRepo := db.Media()
 
 	t.Run("CreateMedia", func(t *testing.T) {
 		// Create a test memory first
@@ -28,8 +29,8 @@ func TestMediaRepo(t *testing.T) {
 			Description: "This is a test memory for media.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "media"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -53,6 +54,15 @@ func TestMediaRepo(t *testing.T) {
 		defer deleteMemory(t, db, memoryID)
 	})
 
+	t.Run("CreateMediaUnknownMemory", func(t *testing.T) {
+		_, err := mediaRepo.CreateMedia(context.Background(), &models.CreateMediaModel{
+			MemoryID: uuid.New().String(),
+			Type:     "image",
+			URL:      "https://example.com/orphan.jpg",
+		})
+		assert.ErrorIs(t, err, storage.ErrMemoryNotFound)
+	})
+
 	t.Run("GetMediaByID", func(t *testing.T) {
 		// Create a test memory first
 		createMemoryModel := &models.CreateMemoryModel{
@@ -61,8 +71,8 @@ func TestMediaRepo(t *testing.T) {
 			Description: "This is a test memory for media.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "media"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -100,8 +110,8 @@ func TestMediaRepo(t *testing.T) {
 			Description: "This is a test memory for media.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "media"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -133,12 +143,20 @@ func TestMediaRepo(t *testing.T) {
 		// Test GetAllMedia with no filters
 		mediaList, err := mediaRepo.GetAllMedia(context.Background(), &memory.GetAllMediaRequest{})
 		assert.NoError(t, err)
-		assert.GreaterOrEqual(t, len(mediaList), 2) // At least 2 media should be returned
+		assert.GreaterOrEqual(t, len(mediaList.Media), 2) // At least 2 media should be returned
+		assert.GreaterOrEqual(t, mediaList.Count, int32(2))
 
 		// Test GetAllMedia with memoryID filter
 		mediaList, err = mediaRepo.GetAllMedia(context.Background(), &memory.GetAllMediaRequest{MemoryId: memoryID})
 		assert.NoError(t, err)
-		assert.GreaterOrEqual(t, len(mediaList), 2) // At least 2 media should be returned for this memory
+		assert.Len(t, mediaList.Media, 2) // Exactly the 2 media of this memory
+		assert.Equal(t, int32(2), mediaList.Count)
+
+		// Test GetAllMedia pagination
+		mediaList, err = mediaRepo.GetAllMedia(context.Background(), &memory.GetAllMediaRequest{MemoryId: memoryID, Page: 2, Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, mediaList.Media, 1)
+		assert.Equal(t, int32(2), mediaList.Count)
 
 		// Cleanup
 		defer deleteMedia(t, db, createdID1)
@@ -154,8 +172,8 @@ func TestMediaRepo(t *testing.T) {
 			Description: "This is a test memory for media.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "media"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -196,6 +214,42 @@ func TestMediaRepo(t *testing.T) {
 		defer deleteMemory(t, db, memoryID)
 	})
 
+	t.Run("UpdateMediaKeepsCreatedAt", func(t *testing.T) {
+		memoryID, err := memoryRepo.CreateMemory(context.Background(), &models.CreateMemoryModel{
+			UserID:  uuid.New().String(),
+			Title:   "Test Memory for Media",
+			Date:    time.Now(),
+			Privacy: "public",
+		})
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, memoryID)
+
+		createdID, err := mediaRepo.CreateMedia(context.Background(), &models.CreateMediaModel{
+			MemoryID: memoryID,
+			Type:     "image",
+			URL:      "https://example.com/image.jpg",
+		})
+		assert.NoError(t, err)
+		defer deleteMedia(t, db, createdID)
+
+		created, err := mediaRepo.GetMediaByID(context.Background(), createdID)
+		assert.NoError(t, err)
+
+		// Without a creation time the stored one is kept.
+		err = mediaRepo.UpdateMedia(context.Background(), &models.UpdateMediaModel{
+			ID:       createdID,
+			MemoryID: memoryID,
+			Type:     "video",
+			URL:      "https://example.com/video.mp4",
+		})
+		assert.NoError(t, err)
+
+		updated, err := mediaRepo.GetMediaByID(context.Background(), createdID)
+		assert.NoError(t, err)
+		assert.Equal(t, "video", updated.Type)
+		assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+	})
+
 	t.Run("PatchMedia", func(t *testing.T) {
 		// Create a test memory first
 		createMemoryModel := &models.CreateMemoryModel{
@@ -204,8 +258,8 @@ func TestMediaRepo(t *testing.T) {
 			Description: "This is a test memory for media.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "media"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -250,8 +304,8 @@ func TestMediaRepo(t *testing.T) {
 			Description: "This is a test memory for media.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "media"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -274,7 +328,7 @@ func TestMediaRepo(t *testing.T) {
 		assert.NoError(t, err)
 
 		_, err = mediaRepo.GetMediaByID(context.Background(), createdID)
-		assert.ErrorIs(t, err, pgx.ErrNoRows) // Media should not be found
+		assert.ErrorIs(t, err, storage.ErrNotFound) // Media should not be found
 
 		defer deleteMemory(t, db, memoryID)
 	})
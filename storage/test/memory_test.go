@@ -7,36 +7,19 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
 	"github.com/time_capsule/memory-service/models"
-	"github.com/time_capsule/memory-service/storage/postgres"
+	"github.com/time_capsule/memory-service/storage"
 )
 
-func createDBConnection(t *testing.T) *pgx.Conn {
-
-	dbCon := fmt.Sprintf("postgresql://%s:%s@%s:%d/%s",
-		"sayyidmuhammad",
-		"root",
-		"localhost",
-		5432,
-		"postgres",
-	)
-
-	// Connecting to postgres
-	db, err := pgx.Connect(context.Background(), dbCon)
-	if err != nil {
-		t.Fatalf("Unable to connect to database: %v", err)
-	}
-	return db
-}
-
 func TestMemoryRepo(t *testing.T) {
-	db := createDBConnection(t)
-	defer db.Close(context.Background())
+	forEachBackend(t, testMemoryRepo)
+}
 
-	memoryRepo := postgres.NewMemoryRepo(db)
+func testMemoryRepo(t *testing.T, db *testBackend) {
+	memoryRepo := db.Memory()
 
 	t.Run("CreateMemory", func(t *testing.T) {
 		createMemoryModel := &models.CreateMemoryModel{
@@ -45,8 +28,8 @@ func TestMemoryRepo(t *testing.T) {
 			Description: "This is a test memory.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "memory"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -66,8 +49,8 @@ func TestMemoryRepo(t *testing.T) {
 			Description: "This is a test memory.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "memory"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -93,8 +76,8 @@ func TestMemoryRepo(t *testing.T) {
 			Description: "This is test memory 1.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "memory"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -108,8 +91,8 @@ func TestMemoryRepo(t *testing.T) {
 			Description: "This is test memory 2.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "memory"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -120,13 +103,148 @@ func TestMemoryRepo(t *testing.T) {
 		// Test GetAllMemories
 		memories, err := memoryRepo.GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{})
 		assert.NoError(t, err)
-		assert.GreaterOrEqual(t, len(memories), 2) // At least 2 memories should be returned
+		assert.GreaterOrEqual(t, len(memories.Memories), 2) // At least 2 memories should be returned
+		assert.GreaterOrEqual(t, memories.Count, int32(2))
+
+		// Test GetAllMemories pagination
+		memories, err = memoryRepo.GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{UserId: createMemoryModel1.UserID, Page: 1, Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, memories.Memories, 1)
+		assert.Equal(t, int32(1), memories.Count)
 
 		// Cleanup
 		defer deleteMemory(t, db, createdID1)
 		defer deleteMemory(t, db, createdID2)
 	})
 
+	t.Run("GetAllMemoriesPageToken", func(t *testing.T) {
+		userID := uuid.New().String()
+		var createdIDs []string
+		for i := 0; i < 3; i++ {
+			createdID, err := memoryRepo.CreateMemory(context.Background(), &models.CreateMemoryModel{
+				UserID:  userID,
+				Title:   fmt.Sprintf("Timeline Memory %d", i),
+				Date:    time.Now().Add(-time.Duration(i) * time.Hour),
+				Privacy: "public",
+			})
+			assert.NoError(t, err)
+			createdIDs = append(createdIDs, createdID)
+			defer deleteMemory(t, db, createdID)
+		}
+
+		// Walk the timeline one memory at a time using the returned cursor
+		var seen []string
+		req := &memory.GetAllMemoriesRequest{UserId: userID, Limit: 1}
+		for {
+			page, err := memoryRepo.GetAllMemories(context.Background(), req)
+			assert.NoError(t, err)
+			assert.Equal(t, int32(3), page.Count)
+			for _, m := range page.Memories {
+				seen = append(seen, m.Id)
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			req.PageToken = page.NextPageToken
+		}
+		assert.Equal(t, createdIDs, seen) // Newest first, no duplicates or gaps
+	})
+
+	t.Run("SearchMemories", func(t *testing.T) {
+		userID := uuid.New().String()
+		createdID, err := memoryRepo.CreateMemory(context.Background(), &models.CreateMemoryModel{
+			UserID:      userID,
+			Title:       "Graduation day",
+			Description: "We were graduating from university together.",
+			Date:        time.Now(),
+			Tags:        []string{"family"},
+			PlaceName:   "Tashkent",
+			Privacy:     "public",
+		})
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, createdID)
+
+		// Stemming matches "graduated" against "graduation"/"graduating"
+		memories, err := memoryRepo.GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{
+			UserId:     userID,
+			SearchTerm: "graduated",
+			SortBy:     "relevance",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, memories.Memories, 1)
+		assert.Greater(t, memories.Memories[0].SearchRank, 0.0)
+		assert.Contains(t, memories.Memories[0].Highlight, "<b>")
+
+		memories, err = memoryRepo.GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{
+			UserId:     userID,
+			SearchTerm: "wedding",
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, memories.Memories)
+	})
+
+	t.Run("GeoMemories", func(t *testing.T) {
+		userID := uuid.New().String()
+		places := []struct {
+			title    string
+			lat, lon *float64
+		}{
+			{"Null Island", helper.Ptr(0.0), helper.Ptr(0.0)},
+			{"Nearby", helper.Ptr(0.01), helper.Ptr(0.01)},
+			{"Far away", helper.Ptr(41.2995), helper.Ptr(69.2401)},
+			{"Nowhere", nil, nil},
+		}
+		for _, place := range places {
+			createdID, err := memoryRepo.CreateMemory(context.Background(), &models.CreateMemoryModel{
+				UserID:    userID,
+				Title:     place.title,
+				Date:      time.Now(),
+				Latitude:  place.lat,
+				Longitude: place.lon,
+				Privacy:   "public",
+			})
+			assert.NoError(t, err)
+			defer deleteMemory(t, db, createdID)
+		}
+
+		// Radius search around the equator / prime meridian, nearest first
+		memories, err := memoryRepo.GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{
+			UserId:          userID,
+			CenterLatitude:  helper.Ptr(0.0),
+			CenterLongitude: helper.Ptr(0.0),
+			RadiusMeters:    5000,
+			SortBy:          "distance",
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, memories.Memories, 2) {
+			assert.Equal(t, "Null Island", memories.Memories[0].Title)
+			assert.Equal(t, "Nearby", memories.Memories[1].Title)
+			assert.InDelta(t, 1572, memories.Memories[1].DistanceMeters, 5)
+		}
+
+		// Exact coordinate filter works for zero values
+		memories, err = memoryRepo.GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{
+			UserId:    userID,
+			Latitude:  helper.Ptr(0.0),
+			Longitude: helper.Ptr(0.0),
+		})
+		assert.NoError(t, err)
+		assert.Len(t, memories.Memories, 1)
+
+		// Bounding box
+		memories, err = memoryRepo.GetAllMemories(context.Background(), &memory.GetAllMemoriesRequest{
+			UserId:       userID,
+			MinLatitude:  helper.Ptr(40.0),
+			MinLongitude: helper.Ptr(68.0),
+			MaxLatitude:  helper.Ptr(42.0),
+			MaxLongitude: helper.Ptr(70.0),
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, memories.Memories, 1) {
+			assert.Equal(t, "Far away", memories.Memories[0].Title)
+		}
+	})
+
 	t.Run("UpdateMemory", func(t *testing.T) {
 		createMemoryModel := &models.CreateMemoryModel{
 			UserID:      uuid.New().String(),
@@ -134,8 +252,8 @@ func TestMemoryRepo(t *testing.T) {
 			Description: "This is a test memory.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "memory"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -151,8 +269,8 @@ func TestMemoryRepo(t *testing.T) {
 			Description: "Updated memory description.",
 			Date:        time.Now().Add(time.Hour * 24), // Add a day
 			Tags:        []string{"updated", "tags"},
-			Latitude:    35.0522,
-			Longitude:   -119.2437,
+			Latitude:    helper.Ptr(35.0522),
+			Longitude:   helper.Ptr(-119.2437),
 			PlaceName:   "San Francisco",
 			Privacy:     "private",
 		}
@@ -177,8 +295,8 @@ func TestMemoryRepo(t *testing.T) {
 			Description: "This is a test memory.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "memory"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -203,6 +321,47 @@ func TestMemoryRepo(t *testing.T) {
 		defer deleteMemory(t, db, createdID)
 	})
 
+	t.Run("MemoryVersion", func(t *testing.T) {
+		createMemoryModel := &models.CreateMemoryModel{
+			UserID:      uuid.New().String(),
+			Title:       "Versioned Memory",
+			Description: "This memory is edited concurrently.",
+			Date:        time.Now(),
+			Privacy:     "public",
+		}
+
+		createdID, err := memoryRepo.CreateMemory(context.Background(), createMemoryModel)
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, createdID)
+
+		created, err := memoryRepo.GetMemoryByID(context.Background(), createdID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), created.Version)
+		assert.NotEmpty(t, created.UpdatedAt)
+
+		title := "First edit"
+		err = memoryRepo.PatchMemory(context.Background(), &models.PatchMemoryModel{
+			ID:              createdID,
+			Title:           &title,
+			ExpectedVersion: created.Version,
+		})
+		assert.NoError(t, err)
+
+		// A second writer still holding version 1 must not overwrite the edit.
+		title = "Stale edit"
+		err = memoryRepo.PatchMemory(context.Background(), &models.PatchMemoryModel{
+			ID:              createdID,
+			Title:           &title,
+			ExpectedVersion: created.Version,
+		})
+		assert.ErrorIs(t, err, storage.ErrVersionConflict)
+
+		patched, err := memoryRepo.GetMemoryByID(context.Background(), createdID)
+		assert.NoError(t, err)
+		assert.Equal(t, "First edit", patched.Title)
+		assert.Equal(t, int64(2), patched.Version)
+	})
+
 	t.Run("DeleteMemory", func(t *testing.T) {
 		createMemoryModel := &models.CreateMemoryModel{
 			UserID:      uuid.New().String(),
@@ -210,8 +369,8 @@ func TestMemoryRepo(t *testing.T) {
 			Description: "This is a test memory.",
 			Date:        time.Now(),
 			Tags:        []string{"test", "memory"},
-			Latitude:    34.0522,
-			Longitude:   -118.2437,
+			Latitude:    helper.Ptr(34.0522),
+			Longitude:   helper.Ptr(-118.2437),
 			PlaceName:   "Los Angeles",
 			Privacy:     "public",
 		}
@@ -220,26 +379,173 @@ func TestMemoryRepo(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotEmpty(t, createdID)
 
-		err = memoryRepo.DeleteMemory(context.Background(), createdID)
+		_, err = memoryRepo.DeleteMemory(context.Background(), createdID)
 		assert.NoError(t, err)
 
 		_, err = memoryRepo.GetMemoryByID(context.Background(), createdID)
-		assert.ErrorIs(t, err, pgx.ErrNoRows) // Memory should not be found
+		assert.ErrorIs(t, err, storage.ErrNotFound) // Memory should not be found
 	})
 }
 
-// Helper functions for cleanup
-func deleteMemory(t *testing.T, db *pgx.Conn, id string) {
-	_, err := db.Exec(context.Background(), "DELETE FROM memories WHERE id = $1", id)
-	assert.NoError(t, err)
+func TestMemoryTrash(t *testing.T) {
+	forEachBackend(t, testMemoryTrash)
 }
 
-func deleteMedia(t *testing.T, db *pgx.Conn, id string) {
-	_, err := db.Exec(context.Background(), "DELETE FROM media WHERE id = $1", id)
+func testMemoryTrash(t *testing.T, db *testBackend) {
+	memoryRepo := db.Memory()
+	mediaRepo := db.Media()
+	commentRepo := db.Comment()
+
+	userID := uuid.New().String()
+	memoryID, err := memoryRepo.CreateMemory(context.Background(), &models.CreateMemoryModel{
+		UserID:  userID,
+		Title:   "Trashed Memory",
+		Date:    time.Now(),
+		Privacy: "private",
+	})
+	assert.NoError(t, err)
+	defer deleteMemory(t, db, memoryID)
+
+	mediaID, err := mediaRepo.CreateMedia(context.Background(), &models.CreateMediaModel{
+		MemoryID: memoryID,
+		Type:     "image",
+		URL:      "https://example.com/image.jpg",
+	})
+	assert.NoError(t, err)
+
+	commentID, err := commentRepo.CreateComment(context.Background(), &models.CreateCommentModel{
+		MemoryID: memoryID,
+		UserID:   userID,
+		Content:  "Nice!",
+	})
+	assert.NoError(t, err)
+
+	// Deleting moves the memory and its children to the trash
+	deleted, err := memoryRepo.DeleteMemory(context.Background(), memoryID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), deleted.DeletedMedia)
+	assert.Equal(t, int32(1), deleted.DeletedComments)
+
+	_, err = mediaRepo.GetMediaByID(context.Background(), mediaID)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = commentRepo.GetCommentByID(context.Background(), commentID)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	trash, err := memoryRepo.GetDeletedMemories(context.Background(), &memory.GetDeletedMemoriesRequest{UserId: userID})
 	assert.NoError(t, err)
+	if assert.Len(t, trash.Memories, 1) {
+		assert.Equal(t, memoryID, trash.Memories[0].Id)
+		assert.NotEmpty(t, trash.Memories[0].DeletedAt)
+	}
+
+	// Children cannot be restored while their memory is in the trash
+	err = mediaRepo.RestoreMedia(context.Background(), mediaID)
+	assert.Error(t, err)
+
+	// Restoring the memory brings its children back too
+	err = memoryRepo.RestoreMemory(context.Background(), memoryID)
+	assert.NoError(t, err)
+
+	_, err = memoryRepo.GetMemoryByID(context.Background(), memoryID)
+	assert.NoError(t, err)
+	_, err = mediaRepo.GetMediaByID(context.Background(), mediaID)
+	assert.NoError(t, err)
+	_, err = commentRepo.GetCommentByID(context.Background(), commentID)
+	assert.NoError(t, err)
+
+	// Purging only removes items trashed before the cutoff
+	_, err = memoryRepo.DeleteMemory(context.Background(), memoryID)
+	assert.NoError(t, err)
+
+	purged, err := memoryRepo.PurgeMemories(context.Background(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = memoryRepo.PurgeMemories(context.Background(), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, purged, int64(1))
+
+	err = memoryRepo.RestoreMemory(context.Background(), memoryID)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func deleteComment(t *testing.T, db *pgx.Conn, id string) {
-	_, err := db.Exec(context.Background(), "DELETE FROM comments WHERE id = $1", id)
+// Helper functions for cleanup
+func TestMemoryRevisions(t *testing.T) {
+	forEachBackend(t, testMemoryRevisions)
+}
+
+func testMemoryRevisions(t *testing.T, db *testBackend) {
+	memoryRepo := db.Memory()
+
+	userID := uuid.New().String()
+	editorID := uuid.New().String()
+	memoryID, err := memoryRepo.CreateMemory(context.Background(), &models.CreateMemoryModel{
+		UserID:  userID,
+		Title:   "Original title",
+		Date:    time.Now(),
+		Tags:    []string{"first"},
+		Privacy: "private",
+	})
+	assert.NoError(t, err)
+	defer deleteMemory(t, db, memoryID)
+
+	title := "Regretted title"
+	err = memoryRepo.PatchMemory(context.Background(), &models.PatchMemoryModel{
+		ID:        memoryID,
+		Title:     &title,
+		ChangedBy: editorID,
+	})
+	assert.NoError(t, err)
+
+	revisions, err := memoryRepo.ListMemoryRevisions(context.Background(), &memory.ListMemoryRevisionsRequest{MemoryId: memoryID})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), revisions.Count)
+
+	latest := revisions.Revisions[0]
+	assert.Equal(t, int64(2), latest.Version)
+	assert.Equal(t, editorID, latest.ChangedBy)
+	if assert.Len(t, latest.Changes, 1) {
+		assert.Equal(t, "title", latest.Changes[0].Field)
+		assert.Equal(t, `"Original title"`, latest.Changes[0].OldValue)
+		assert.Equal(t, `"Regretted title"`, latest.Changes[0].NewValue)
+	}
+
+	first := revisions.Revisions[1]
+	assert.Equal(t, userID, first.ChangedBy) // Creation is attributed to the owner
+
+	err = memoryRepo.RestoreMemoryRevision(context.Background(), &memory.RestoreMemoryRevisionRequest{
+		MemoryId:   memoryID,
+		RevisionId: first.Id,
+		ChangedBy:  editorID,
+	})
 	assert.NoError(t, err)
+
+	restored, err := memoryRepo.GetMemoryByID(context.Background(), memoryID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Original title", restored.Title)
+	assert.Equal(t, int64(3), restored.Version)
+
+	revisions, err = memoryRepo.ListMemoryRevisions(context.Background(), &memory.ListMemoryRevisionsRequest{MemoryId: memoryID})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), revisions.Count)
+
+	asOf, err := memoryRepo.GetMemoryAsOf(context.Background(), memoryID, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "Original title", asOf.Title)
+	assert.Equal(t, int64(3), asOf.Version)
+
+	_, err = memoryRepo.GetMemoryAsOf(context.Background(), memoryID, time.Now().Add(-24*time.Hour))
+	assert.ErrorIs(t, err, storage.ErrNotFound) // The memory did not exist yet
+}
+
+func deleteMemory(t *testing.T, db *testBackend, id string) {
+	assert.NoError(t, db.remove(context.Background(), "memories", id))
+}
+
+func deleteMedia(t *testing.T, db *testBackend, id string) {
+	assert.NoError(t, db.remove(context.Background(), "media", id))
+}
+
+func deleteComment(t *testing.T, db *testBackend, id string) {
+	assert.NoError(t, db.remove(context.Background(), "comments", id))
 }
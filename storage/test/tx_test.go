@@ -0,0 +1,132 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+func TestWithTx(t *testing.T) {
+	forEachBackend(t, testWithTx)
+}
+
+func testWithTx(t *testing.T, db *testBackend) {
+	ctx := context.Background()
+
+	// createMemoryWithMedia writes a memory and its media in one transaction.
+	createMemoryWithMedia := func(tx storage.StorageI) (string, string, error) {
+		memoryID, err := tx.Memory().CreateMemory(ctx, &models.CreateMemoryModel{
+			UserID:  uuid.New().String(),
+			Title:   "Transactional Memory",
+			Date:    time.Now(),
+			Privacy: "private",
+		})
+		if err != nil {
+			return "", "", err
+		}
+		mediaID, err := tx.Media().CreateMedia(ctx, &models.CreateMediaModel{
+			MemoryID: memoryID,
+			Type:     "image",
+			URL:      "https://example.com/image.jpg",
+		})
+		return memoryID, mediaID, err
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		var memoryID, mediaID string
+		err := db.WithTx(ctx, func(tx storage.StorageI) error {
+			var err error
+			memoryID, mediaID, err = createMemoryWithMedia(tx)
+			return err
+		})
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, memoryID)
+
+		_, err = db.Memory().GetMemoryByID(ctx, memoryID)
+		assert.NoError(t, err)
+		_, err = db.Media().GetMediaByID(ctx, mediaID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		var memoryID, mediaID string
+		err := db.WithTx(ctx, func(tx storage.StorageI) error {
+			var err error
+			if memoryID, mediaID, err = createMemoryWithMedia(tx); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		assert.ErrorIs(t, err, errAbort)
+
+		_, err = db.Memory().GetMemoryByID(ctx, memoryID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = db.Media().GetMediaByID(ctx, mediaID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("RollbackOnPanic", func(t *testing.T) {
+		var memoryID string
+		assert.PanicsWithValue(t, "boom", func() {
+			db.WithTx(ctx, func(tx storage.StorageI) error {
+				var err error
+				if memoryID, _, err = createMemoryWithMedia(tx); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		})
+
+		_, err := db.Memory().GetMemoryByID(ctx, memoryID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("CascadeInTx", func(t *testing.T) {
+		memoryID, mediaID, err := createMemoryWithMedia(db)
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, memoryID)
+
+		// Deleting the memory and creating a comment on it cannot both
+		// succeed, so neither takes effect.
+		err = db.WithTx(ctx, func(tx storage.StorageI) error {
+			if _, err := tx.Memory().DeleteMemory(ctx, memoryID); err != nil {
+				return err
+			}
+			_, err := tx.Comment().CreateComment(ctx, &models.CreateCommentModel{
+				MemoryID: memoryID,
+				UserID:   uuid.New().String(),
+				Content:  "Too late",
+			})
+			return err
+		})
+		assert.ErrorIs(t, err, storage.ErrMemoryNotFound)
+
+		_, err = db.Memory().GetMemoryByID(ctx, memoryID)
+		assert.NoError(t, err)
+		_, err = db.Media().GetMediaByID(ctx, mediaID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		var memoryID, mediaID string
+		err := db.WithTx(ctx, func(tx storage.StorageI) error {
+			return tx.WithTx(ctx, func(tx storage.StorageI) error {
+				var err error
+				memoryID, mediaID, err = createMemoryWithMedia(tx)
+				return err
+			})
+		})
+		assert.NoError(t, err)
+		defer deleteMemory(t, db, memoryID)
+
+		_, err = db.Media().GetMediaByID(ctx, mediaID)
+		assert.NoError(t, err)
+	})
+}
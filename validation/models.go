@@ -0,0 +1,164 @@
+package validation
+
+import (
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/models"
+)
+
+// CreateMemory validates memory and normalizes its tags.
+func CreateMemory(memory *models.CreateMemoryModel) error {
+	var v violations
+	v.required("user_id", memory.UserID)
+	v.required("title", memory.Title)
+	v.maxLength("title", memory.Title, MaxTitleLength)
+	v.maxLength("description", memory.Description, MaxDescriptionLength)
+	v.date("date", memory.Date)
+	v.tags(&memory.Tags)
+	v.coordinates(memory.Latitude, memory.Longitude)
+	v.maxLength("place_name", memory.PlaceName, MaxPlaceNameLength)
+	v.oneOf("privacy", memory.Privacy, Privacies)
+	v.language("language", memory.Language)
+	return v.err()
+}
+
+// UpdateMemory validates memory and normalizes its tags.
+func UpdateMemory(memory *models.UpdateMemoryModel) error {
+	var v violations
+	v.required("id", memory.ID)
+	v.required("user_id", memory.UserID)
+	v.required("title", memory.Title)
+	v.maxLength("title", memory.Title, MaxTitleLength)
+	v.maxLength("description", memory.Description, MaxDescriptionLength)
+	v.date("date", memory.Date)
+	v.tags(&memory.Tags)
+	v.coordinates(memory.Latitude, memory.Longitude)
+	v.maxLength("place_name", memory.PlaceName, MaxPlaceNameLength)
+	v.oneOf("privacy", memory.Privacy, Privacies)
+	v.language("language", memory.Language)
+	v.optionalUUID("changed_by", memory.ChangedBy)
+	return v.err()
+}
+
+// PatchMemory validates the fields set in memory and normalizes its tags.
+func PatchMemory(memory *models.PatchMemoryModel) error {
+	var v violations
+	v.required("id", memory.ID)
+	if memory.Title != nil {
+		v.required("title", *memory.Title)
+		v.maxLength("title", *memory.Title, MaxTitleLength)
+	}
+	if memory.Description != nil {
+		v.maxLength("description", *memory.Description, MaxDescriptionLength)
+	}
+	if memory.Date != nil {
+		v.date("date", *memory.Date)
+	}
+	v.tags(memory.Tags)
+	v.coordinates(memory.Latitude, memory.Longitude)
+	if memory.PlaceName != nil {
+		v.maxLength("place_name", *memory.PlaceName, MaxPlaceNameLength)
+	}
+	if memory.Privacy != nil {
+		v.oneOf("privacy", *memory.Privacy, Privacies)
+	}
+	if memory.Language != nil {
+		v.oneOf("language", *memory.Language, Languages)
+	}
+	v.optionalUUID("changed_by", memory.ChangedBy)
+	return v.err()
+}
+
+// GetAllMemories validates the filters of req.
+func GetAllMemories(req *memory.GetAllMemoriesRequest) error {
+	var v violations
+	v.language("search_language", req.SearchLanguage)
+	return v.err()
+}
+
+// RestoreMemoryRevision validates req.
+func RestoreMemoryRevision(req *memory.RestoreMemoryRevisionRequest) error {
+	var v violations
+	v.required("memory_id", req.MemoryId)
+	v.required("revision_id", req.RevisionId)
+	v.optionalUUID("changed_by", req.ChangedBy)
+	return v.err()
+}
+
+// CreateMedia validates media.
+func CreateMedia(media *models.CreateMediaModel) error {
+	var v violations
+	v.required("memory_id", media.MemoryID)
+	v.oneOf("type", media.Type, MediaTypes)
+	v.url("url", media.URL)
+	return v.err()
+}
+
+// UpdateMedia validates media.
+func UpdateMedia(media *models.UpdateMediaModel) error {
+	var v violations
+	v.required("id", media.ID)
+	v.required("memory_id", media.MemoryID)
+	v.oneOf("type", media.Type, MediaTypes)
+	v.url("url", media.URL)
+	return v.err()
+}
+
+// PatchMedia validates the fields set in media.
+func PatchMedia(media *models.PatchMediaModel) error {
+	var v violations
+	v.required("id", media.ID)
+	if media.MemoryID != nil {
+		v.required("memory_id", *media.MemoryID)
+	}
+	if media.Type != nil {
+		v.oneOf("type", *media.Type, MediaTypes)
+	}
+	if media.URL != nil {
+		v.url("url", *media.URL)
+	}
+	if media.Created != nil {
+		v.date("created_at", *media.Created)
+	}
+	return v.err()
+}
+
+// CreateComment validates comment.
+func CreateComment(comment *models.CreateCommentModel) error {
+	var v violations
+	v.required("memory_id", comment.MemoryID)
+	v.required("user_id", comment.UserID)
+	v.required("content", comment.Content)
+	v.maxLength("content", comment.Content, MaxContentLength)
+	return v.err()
+}
+
+// UpdateComment validates comment.
+func UpdateComment(comment *models.UpdateCommentModel) error {
+	var v violations
+	v.required("id", comment.ID)
+	v.required("memory_id", comment.MemoryID)
+	v.required("user_id", comment.UserID)
+	v.required("content", comment.Content)
+	v.maxLength("content", comment.Content, MaxContentLength)
+	return v.err()
+}
+
+// PatchComment validates the fields set in comment.
+func PatchComment(comment *models.PatchCommentModel) error {
+	var v violations
+	v.required("id", comment.ID)
+	if comment.MemoryID != nil {
+		v.required("memory_id", *comment.MemoryID)
+	}
+	if comment.UserID != nil {
+		v.required("user_id", *comment.UserID)
+	}
+	if comment.Content != nil {
+		v.required("content", *comment.Content)
+		v.maxLength("content", *comment.Content, MaxContentLength)
+	}
+	if comment.Created != nil {
+		v.date("created_at", *comment.Created)
+	}
+	return v.err()
+}
@@ -0,0 +1,183 @@
+// Package validation checks the models written through the gRPC and Kafka
+// paths before they reach storage.
+package validation
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/time_capsule/memory-service/storage"
+)
+
+// Limits on the values of a model.
+const (
+	MaxTitleLength       = 255
+	MaxDescriptionLength = 5000
+	MaxPlaceNameLength   = 255
+	MaxTags              = 20
+	MaxTagLength         = 50
+	MaxURLLength         = 2048
+	MaxContentLength     = 2000
+)
+
+var (
+	// Privacies lists the valid privacy settings of a memory.
+	Privacies = []string{"public", "private"}
+
+	// MediaTypes lists the valid media types.
+	MediaTypes = []string{"image", "video", "audio"}
+
+	// URLSchemes lists the schemes a media URL may use.
+	URLSchemes = []string{"http", "https"}
+
+	// Languages lists the text search languages of memories: the text
+	// search configurations PostgreSQL ships with since version 13.
+	Languages = []string{
+		"simple", "arabic", "danish", "dutch", "english", "finnish", "french",
+		"german", "greek", "hungarian", "indonesian", "irish", "italian",
+		"lithuanian", "nepali", "norwegian", "portuguese", "romanian",
+		"russian", "spanish", "swedish", "tamil", "turkish",
+	}
+)
+
+// FieldViolation describes why a field is invalid.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Error lists the field violations of an invalid model. It is a
+// storage.ErrInvalidArgument.
+type Error struct {
+	Violations []FieldViolation
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + ": " + v.Description
+	}
+	return fmt.Sprintf("%s: %s", storage.ErrInvalidArgument, strings.Join(msgs, "; "))
+}
+
+func (e *Error) Is(target error) bool {
+	return target == storage.ErrInvalidArgument
+}
+
+// violations collects the field violations of a model.
+type violations []FieldViolation
+
+func (v *violations) add(field, format string, args ...interface{}) {
+	*v = append(*v, FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+// err returns the collected violations as an *Error, or nil if there are
+// none.
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &Error{Violations: v}
+}
+
+func (v *violations) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "must not be empty")
+	}
+}
+
+// optionalUUID checks a field that is either empty or a UUID.
+func (v *violations) optionalUUID(field, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		v.add(field, "must be a UUID")
+	}
+}
+
+func (v *violations) maxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(field, "must be at most %d characters", max)
+	}
+}
+
+func (v *violations) oneOf(field, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(field, "must be one of %s", strings.Join(allowed, ", "))
+}
+
+// language checks a text search language. Empty selects the default.
+func (v *violations) language(field, value string) {
+	if value != "" {
+		v.oneOf(field, value, Languages)
+	}
+}
+
+func (v *violations) date(field string, value time.Time) {
+	if value.IsZero() {
+		v.add(field, "must be set")
+	}
+}
+
+func (v *violations) coordinate(field string, value *float64, max float64) {
+	if value != nil && (math.IsNaN(*value) || *value < -max || *value > max) {
+		v.add(field, "must be between %g and %g", -max, max)
+	}
+}
+
+// coordinates checks a latitude and longitude that are written together.
+func (v *violations) coordinates(latitude, longitude *float64) {
+	v.coordinate("latitude", latitude, 90)
+	v.coordinate("longitude", longitude, 180)
+	if (latitude == nil) != (longitude == nil) {
+		v.add("latitude", "must be set together with longitude")
+	}
+}
+
+// tags normalizes tags in place and checks the result. Tags are trimmed and
+// lowercased; empty and duplicate tags are dropped.
+func (v *violations) tags(tags *[]string) {
+	if tags == nil || *tags == nil {
+		return
+	}
+	normalized := make([]string, 0, len(*tags))
+	seen := make(map[string]bool, len(*tags))
+	for _, tag := range *tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			v.add("tags", "tag %q must be at most %d characters", tag, MaxTagLength)
+		}
+	}
+	if len(normalized) > MaxTags {
+		v.add("tags", "must have at most %d tags", MaxTags)
+	}
+	*tags = normalized
+}
+
+func (v *violations) url(field, value string) {
+	if utf8.RuneCountInString(value) > MaxURLLength {
+		v.add(field, "must be at most %d characters", MaxURLLength)
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		v.add(field, "must be an absolute URL")
+		return
+	}
+	v.oneOf(field+" scheme", strings.ToLower(u.Scheme), URLSchemes)
+}
@@ -0,0 +1,150 @@
+package validation_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/time_capsule/memory-service/genproto/memory"
+	"github.com/time_capsule/memory-service/helper"
+	"github.com/time_capsule/memory-service/models"
+	"github.com/time_capsule/memory-service/storage"
+	"github.com/time_capsule/memory-service/validation"
+)
+
+// violatedFields returns the fields reported by a validation error.
+func violatedFields(t *testing.T, err error) []string {
+	var invalid *validation.Error
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	var fields []string
+	for _, v := range invalid.Violations {
+		fields = append(fields, v.Field)
+	}
+	return fields
+}
+
+func TestMemory(t *testing.T) {
+	valid := func() *models.CreateMemoryModel {
+		return &models.CreateMemoryModel{
+			UserID:    uuid.NewString(),
+			Title:     "Graduation",
+			Date:      time.Now(),
+			Tags:      []string{" School", "school", "", "Family "},
+			Latitude:  helper.Ptr(41.3),
+			Longitude: helper.Ptr(69.2),
+			Privacy:   "public",
+		}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		memory := valid()
+		assert.NoError(t, validation.CreateMemory(memory))
+		assert.Equal(t, []string{"school", "family"}, memory.Tags)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		memory := valid()
+		memory.Title = " "
+		memory.Privacy = "pubic"
+		memory.Latitude = helper.Ptr(500.0)
+		memory.Tags = []string{strings.Repeat("x", validation.MaxTagLength+1)}
+
+		err := validation.CreateMemory(memory)
+		assert.ErrorIs(t, err, storage.ErrInvalidArgument)
+		assert.ElementsMatch(t, []string{"title", "privacy", "latitude", "tags"}, violatedFields(t, err))
+	})
+
+	t.Run("CoordinatesTogether", func(t *testing.T) {
+		memory := valid()
+		memory.Longitude = nil
+		assert.Equal(t, []string{"latitude"}, violatedFields(t, validation.CreateMemory(memory)))
+	})
+
+	t.Run("Patch", func(t *testing.T) {
+		patch := &models.PatchMemoryModel{
+			ID:   uuid.NewString(),
+			Tags: &[]string{"A", "a"},
+		}
+		assert.NoError(t, validation.PatchMemory(patch))
+		assert.Equal(t, []string{"a"}, *patch.Tags)
+
+		patch.Title = helper.Ptr("")
+		assert.Equal(t, []string{"title"}, violatedFields(t, validation.PatchMemory(patch)))
+	})
+
+	t.Run("PatchCoordinatesTogether", func(t *testing.T) {
+		patch := &models.PatchMemoryModel{ID: uuid.NewString(), Latitude: helper.Ptr(41.3)}
+		assert.Equal(t, []string{"latitude"}, violatedFields(t, validation.PatchMemory(patch)))
+
+		patch.Longitude = helper.Ptr(69.2)
+		assert.NoError(t, validation.PatchMemory(patch))
+	})
+
+	t.Run("Language", func(t *testing.T) {
+		create := valid()
+		create.Language = "german"
+		assert.NoError(t, validation.CreateMemory(create))
+
+		// Unknown text search configurations fail in PostgreSQL.
+		create.Language = "klingon"
+		assert.Equal(t, []string{"language"}, violatedFields(t, validation.CreateMemory(create)))
+
+		patch := &models.PatchMemoryModel{ID: uuid.NewString(), Language: helper.Ptr("")}
+		assert.Equal(t, []string{"language"}, violatedFields(t, validation.PatchMemory(patch)))
+
+		err := validation.GetAllMemories(&memory.GetAllMemoriesRequest{SearchTerm: "beach", SearchLanguage: "klingon"})
+		assert.Equal(t, []string{"search_language"}, violatedFields(t, err))
+	})
+
+	t.Run("ChangedBy", func(t *testing.T) {
+		patch := &models.PatchMemoryModel{ID: uuid.NewString(), Title: helper.Ptr("Prom"), ChangedBy: uuid.NewString()}
+		assert.NoError(t, validation.PatchMemory(patch))
+
+		// Revisions record the editor as a UUID.
+		patch.ChangedBy = "alice"
+		assert.Equal(t, []string{"changed_by"}, violatedFields(t, validation.PatchMemory(patch)))
+
+		err := validation.RestoreMemoryRevision(&memory.RestoreMemoryRevisionRequest{
+			MemoryId:   uuid.NewString(),
+			RevisionId: uuid.NewString(),
+			ChangedBy:  "alice",
+		})
+		assert.Equal(t, []string{"changed_by"}, violatedFields(t, err))
+	})
+}
+
+func TestMedia(t *testing.T) {
+	media := &models.CreateMediaModel{
+		MemoryID: uuid.NewString(),
+		Type:     "image",
+		URL:      "https://example.com/image.jpg",
+	}
+	assert.NoError(t, validation.CreateMedia(media))
+
+	media.Type = "photo"
+	media.URL = "ftp://example.com/image.jpg"
+	assert.ElementsMatch(t, []string{"type", "url scheme"}, violatedFields(t, validation.CreateMedia(media)))
+
+	err := validation.PatchMedia(&models.PatchMediaModel{ID: uuid.NewString(), URL: helper.Ptr("image.jpg")})
+	assert.Equal(t, []string{"url"}, violatedFields(t, err))
+}
+
+func TestComment(t *testing.T) {
+	comment := &models.CreateCommentModel{
+		MemoryID: uuid.NewString(),
+		UserID:   uuid.NewString(),
+		Content:  "Nice!",
+	}
+	assert.NoError(t, validation.CreateComment(comment))
+
+	comment.Content = "   "
+	assert.Equal(t, []string{"content"}, violatedFields(t, validation.CreateComment(comment)))
+
+	comment.Content = strings.Repeat("x", validation.MaxContentLength+1)
+	assert.Equal(t, []string{"content"}, violatedFields(t, validation.CreateComment(comment)))
+}